@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"cc-plans-lister/internal/diff"
+	"cc-plans-lister/internal/snapshot"
+)
+
+var (
+	diffFormat string
+	diffOutput string
+)
+
+// diffCmd compares two saved catalogs
+var diffCmd = &cobra.Command{
+	Use:   "diff OLD NEW",
+	Short: "Compare two catalog snapshots",
+	Long: `diff compares two catalogs saved with --save-snapshot (or produced with --format=json)
+and reports added and removed addon providers, plans, instance types and flavors,
+renames, flavor price changes, availability changes and default flavor changes.`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDiff,
+}
+
+func init() {
+	diffCmd.Flags().StringVarP(&diffFormat, "format", "f", "text", "Output format (text, markdown, json)")
+	diffCmd.Flags().StringVarP(&diffOutput, "output", "o", "", "Output file (default: stdout)")
+
+	rootCmd.AddCommand(diffCmd)
+}
+
+func runDiff(cmd *cobra.Command, args []string) error {
+	if !diff.ValidateFormat(diffFormat) {
+		return fmt.Errorf("unsupported diff format: %s (supported: text, markdown, json)", diffFormat)
+	}
+
+	oldSnap, err := snapshot.Load(args[0])
+	if err != nil {
+		return err
+	}
+
+	newSnap, err := snapshot.Load(args[1])
+	if err != nil {
+		return err
+	}
+
+	report := diff.Compare(oldSnap, newSnap)
+
+	output := os.Stdout
+	if diffOutput != "" {
+		output, err = os.Create(diffOutput)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer output.Close()
+	}
+
+	if err := diff.Render(report, diffFormat, output); err != nil {
+		return fmt.Errorf("failed to format diff: %w", err)
+	}
+
+	return nil
+}
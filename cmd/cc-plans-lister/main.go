@@ -2,19 +2,51 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf8"
 
 	"github.com/spf13/cobra"
 
 	"cc-plans-lister/internal/api"
 	"cc-plans-lister/internal/config"
+	"cc-plans-lister/internal/filter"
 	"cc-plans-lister/internal/formatters"
+	"cc-plans-lister/internal/output"
+	"cc-plans-lister/internal/snapshot"
+	"cc-plans-lister/pkg/clevercloud"
 )
 
 var (
 	outputFormat string
 	outputFile   string
+	outputDir    string
+	nameTemplate string
+	pdfFont      string
+	pdfLandscape bool
+	templatePath string
+	csvLayout    string
+	csvDelim     string
+	csvDecimal   string
+	saveSnapshot string
+	fromSnapshot string
+	apiURL       string
+	fetchTimeout time.Duration
+	retries      int
+	retryMaxWait time.Duration
+	cacheTTL     time.Duration
+	noCache      bool
+	refreshCache bool
+	zone         string
+	orgID        string
+	filters      filter.Options
+	minMemory    string
 	version      = "1.0.0"
 )
 
@@ -26,15 +58,51 @@ var rootCmd = &cobra.Command{
 documentation of available addon providers and application instance types with their 
 respective plans and flavors.
 
-The tool supports multiple output formats: markdown, txt, csv, and pdf.
+The tool supports multiple output formats: markdown, txt, csv, xlsx, pdf, html, json, yaml
+and sqlite (in cgo builds), as well as custom Go templates given with --template.
 
-Authentication is required via the CLEVER_API_TOKEN environment variable.`,
+Authentication is required via the CLEVER_API_TOKEN environment variable, unless the
+catalog is read from a snapshot previously saved with --save-snapshot.`,
 	RunE: runList,
 }
 
 func init() {
-	rootCmd.Flags().StringVarP(&outputFormat, "format", "f", "markdown", "Output format (markdown, txt, csv, pdf)")
+	rootCmd.Flags().StringVarP(&outputFormat, "format", "f", formatters.DefaultFormat,
+		fmt.Sprintf("Output format (%s), or a comma-separated list with --output-dir; inferred from the --output extension when not set", strings.Join(formatters.Names(), ", ")))
 	rootCmd.Flags().StringVarP(&outputFile, "output", "o", "", "Output file (default: stdout)")
+	rootCmd.Flags().StringVar(&outputDir, "output-dir", "", "Write one file per format to this directory")
+	rootCmd.Flags().StringVar(&nameTemplate, "filename-template", output.DefaultFilenameTemplate,
+		"File names used with --output-dir ({{.Format}}, {{.Ext}}, {{.Date}}, {{.Timestamp}}, {{.Org}}, {{.Zone}})")
+	rootCmd.MarkFlagsMutuallyExclusive("output", "output-dir")
+	rootCmd.Flags().StringVar(&pdfFont, "pdf-font", "", "TrueType font (.ttf) for PDF reports (default: bundled DejaVu Sans)")
+	rootCmd.Flags().BoolVar(&pdfLandscape, "pdf-landscape", false, "Lay out the wide application flavors table of PDF reports in landscape")
+	rootCmd.Flags().StringVar(&templatePath, "template", "", "Go template file rendered by the template format (html/template for .html templates)")
+	rootCmd.Flags().StringVar(&csvLayout, "csv-layout", formatters.CSVLayoutCombined,
+		fmt.Sprintf("Layout of CSV exports (%s); split writes one file per table in a directory (with --output-dir, named after --filename-template without its extension)", strings.Join(formatters.CSVLayouts, ", ")))
+	rootCmd.Flags().StringVar(&csvDelim, "csv-delimiter", ",", `Field delimiter of CSV exports ("tab" for tabs; ";" by default with --csv-decimal=,)`)
+	rootCmd.Flags().StringVar(&csvDecimal, "csv-decimal", ".", `Decimal separator of prices in CSV exports ("." or ",")`)
+	rootCmd.Flags().StringVar(&apiURL, "api-url", api.DefaultBaseURL, "Clever Cloud API base URL")
+	rootCmd.Flags().DurationVar(&fetchTimeout, "timeout", 2*time.Minute, "Deadline for fetching the catalog (0 to disable)")
+	rootCmd.Flags().IntVar(&retries, "retries", api.DefaultRetryPolicy.MaxRetries, "Retries for transient API failures (0 to disable)")
+	rootCmd.Flags().DurationVar(&retryMaxWait, "retry-max-wait", api.DefaultRetryPolicy.MaxWait, "Maximum wait between retries, including Retry-After")
+	rootCmd.Flags().DurationVar(&cacheTTL, "cache-ttl", api.DefaultCacheTTL, "How long cached API responses are used without revalidation")
+	rootCmd.Flags().BoolVar(&noCache, "no-cache", false, "Do not read or write the response cache")
+	rootCmd.Flags().BoolVar(&refreshCache, "refresh", false, "Revalidate cached API responses regardless of their age")
+	rootCmd.Flags().StringVar(&orgID, "org", "", "Fetch the catalog of this organisation (ORGA_ID), including its private providers and prices")
+	rootCmd.Flags().StringVar(&zone, "zone", "", "Only list addon plans available in this zone (e.g. par); instance types are not filtered by zone")
+	rootCmd.Flags().StringSliceVar(&filters.Providers, "provider", nil, "Only list these addon providers (IDs, comma-separated or repeated)")
+	rootCmd.Flags().StringSliceVar(&filters.InstanceTypes, "instance-type", nil, "Only list these application instance types (e.g. node,go)")
+	rootCmd.Flags().StringSliceVar(&filters.Tags, "tag", nil, "Only list instance types with any of these tags")
+	rootCmd.Flags().StringSliceVar(&filters.Deployments, "deployment", nil, "Only list instance types supporting any of these deployment methods (e.g. git,docker)")
+	rootCmd.Flags().StringVar(&minMemory, "min-mem", "", "Only list flavors with at least this much memory (e.g. 4GiB)")
+	rootCmd.Flags().Float64Var(&filters.MaxPrice, "max-price", 0, "Only list flavors costing at most this hourly price in euros (0 for no limit)")
+	rootCmd.Flags().BoolVar(&filters.AvailableOnly, "available-only", false, "Only list flavors that are currently available")
+	rootCmd.Flags().BoolVar(&filters.MLOnly, "ml-only", false, "Only list machine learning flavors")
+	rootCmd.Flags().BoolVar(&filters.MicroserviceOnly, "microservice-only", false, "Only list flavors usable for microservices")
+	rootCmd.Flags().BoolVar(&filters.IncludeDisabled, "include-disabled", false, "Also list disabled instance types")
+	rootCmd.Flags().BoolVar(&filters.IncludeComingSoon, "include-coming-soon", false, "Also list instance types announced as coming soon")
+	rootCmd.Flags().StringVar(&saveSnapshot, "save-snapshot", "", "Save the fetched catalog to a snapshot file")
+	rootCmd.Flags().StringVar(&fromSnapshot, "from-snapshot", "", "Read the catalog from a snapshot file instead of the API")
 
 	rootCmd.AddCommand(versionCmd)
 }
@@ -47,67 +115,314 @@ var versionCmd = &cobra.Command{
 	},
 }
 
-func runList(cmd *cobra.Command, args []string) error {
-	// Load configuration
-	cfg, err := config.LoadConfig()
+// resolveTargets returns the reports to generate: a single one written to
+// --output (or stdout), or one file per format in --output-dir. Without an
+// explicit --format, --template selects the template format and otherwise
+// the format is inferred from the --output extension.
+func resolveTargets(cmd *cobra.Command) ([]output.Target, error) {
+	list := outputFormat
+	if !cmd.Flags().Changed("format") {
+		if templatePath != "" {
+			list = "template"
+		} else if outputFile != "" {
+			if format, ok := formatters.ByExtension(filepath.Ext(outputFile)); ok {
+				list = format.Name
+			}
+		}
+	}
+
+	formats, err := output.ParseFormats(list)
 	if err != nil {
-		return fmt.Errorf("failed to load configuration: %w", err)
+		return nil, err
 	}
 
-	// Validate output format
-	if !config.ValidateOutputFormat(outputFormat) {
-		return fmt.Errorf("unsupported output format: %s (supported: markdown, txt, csv, pdf)", outputFormat)
+	// The template format writes files named after its template
+	for i, format := range formats {
+		if format.Name == "template" {
+			if templatePath == "" {
+				return nil, fmt.Errorf("the template format requires --template")
+			}
+			formats[i].Extension = formatters.TemplateExtension(templatePath)
+		}
 	}
 
-	// Create API client
-	client := api.NewClient(cfg.APIToken)
+	if outputDir == "" {
+		if len(formats) > 1 {
+			return nil, fmt.Errorf("generating several formats requires --output-dir")
+		}
+		return []output.Target{{Format: formats[0], Path: outputFile}}, nil
+	}
 
-	ctx := context.Background()
+	data := output.NewFilenameData(time.Now(), orgID, zone)
+	return output.Targets(outputDir, nameTemplate, formats, data)
+}
 
-	// Fetch addon providers
-	fmt.Fprintln(os.Stderr, "Fetching addon providers from Clever Cloud API...")
-	providers, err := client.GetAddonProviders(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to fetch addon providers: %w", err)
+// formatterOptions returns the formatter settings given on the command line,
+// checking them so that mistakes are reported before the catalog is fetched
+func formatterOptions(cmd *cobra.Command) (formatters.Options, error) {
+	opts := formatters.Options{
+		PDFFont:      pdfFont,
+		PDFLandscape: pdfLandscape,
+		Template:     templatePath,
+		CSVLayout:    csvLayout,
+		CSVDecimal:   csvDecimal,
 	}
 
-	// Fetch product instances
-	fmt.Fprintln(os.Stderr, "Fetching application instances from Clever Cloud API...")
-	instances, err := client.GetProductInstances(ctx)
+	if pdfFont != "" {
+		if _, err := os.Stat(pdfFont); err != nil {
+			return opts, fmt.Errorf("invalid --pdf-font: %w", err)
+		}
+	}
+	if templatePath != "" {
+		if err := formatters.CheckTemplate(templatePath); err != nil {
+			return opts, fmt.Errorf("invalid --template: %w", err)
+		}
+	}
+
+	// A decimal comma calls for the semicolon delimiter used in European locales
+	delimiter := csvDelim
+	if csvDecimal == "," && !cmd.Flags().Changed("csv-delimiter") {
+		delimiter = ";"
+	}
+	switch {
+	case delimiter == "tab" || delimiter == `\t`:
+		opts.CSVDelimiter = '\t'
+	case utf8.RuneCountInString(delimiter) == 1:
+		opts.CSVDelimiter, _ = utf8.DecodeRuneInString(delimiter)
+	default:
+		return opts, fmt.Errorf("invalid --csv-delimiter %q: expected a single character", delimiter)
+	}
+	if err := formatters.CheckCSVOptions(opts); err != nil {
+		return opts, err
+	}
+
+	return opts, nil
+}
+
+// filterOptions returns the catalog filters given on the command line
+func filterOptions() (filter.Options, error) {
+	opts := filters
+	opts.Zone = zone
+
+	if minMemory != "" {
+		size, err := clevercloud.ParseByteSize(minMemory)
+		if err != nil {
+			return opts, fmt.Errorf("invalid --min-mem: %w", err)
+		}
+		opts.MinMemory = size
+	}
+	if opts.MaxPrice < 0 {
+		return opts, fmt.Errorf("invalid --max-price %g: expected a price of 0 or more", opts.MaxPrice)
+	}
+
+	return opts, nil
+}
+
+func runList(cmd *cobra.Command, args []string) error {
+	// Resolve the outputs before fetching anything so that a typo fails fast
+	targets, err := resolveTargets(cmd)
+	if err != nil {
+		return err
+	}
+	opts, err := formatterOptions(cmd)
 	if err != nil {
-		return fmt.Errorf("failed to fetch product instances: %w", err)
+		return err
+	}
+	filterOpts, err := filterOptions()
+	if err != nil {
+		return err
+	}
+	for _, target := range targets {
+		if writesDirectory(target.Format.New(opts)) && target.Path == "" {
+			return fmt.Errorf("the %s output writes several files: give their directory with --output or --output-dir", target.Format.Name)
+		}
+	}
+
+	var (
+		providers []clevercloud.AddonProvider
+		instances []clevercloud.ProductInstance
+		zones     []string // zones --zone is checked against, besides those of the catalog
+		source    string
+	)
+
+	if fromSnapshot != "" {
+		fmt.Fprintf(os.Stderr, "Loading catalog from snapshot %s...\n", fromSnapshot)
+		snap, err := snapshot.Load(fromSnapshot)
+		if err != nil {
+			return err
+		}
+		providers, instances = snap.Providers, snap.Instances
+		source = "snapshot " + fromSnapshot
+	} else {
+		providers, instances, zones, err = fetchCatalog()
+		if err != nil {
+			return err
+		}
+		source = apiURL
+		if orgID != "" {
+			source += fmt.Sprintf(" (organisation %s)", orgID)
+		}
 	}
 
-	// Get formatter
-	formatter := formatters.GetFormatter(outputFormat)
+	// Persist the catalog before formatting so a formatting failure does not lose it
+	if saveSnapshot != "" {
+		if err := snapshot.Save(saveSnapshot, snapshot.New(providers, instances)); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "Saved catalog snapshot to %s\n", saveSnapshot)
+	}
+
+	if zone != "" {
+		if err := filter.CheckZone(zone, append(zones, filter.CatalogZones(providers)...)); err != nil {
+			return err
+		}
+	}
+
+	// Every format reports on the same selection of the catalog
+	providers, instances = filter.Apply(providers, instances, filterOpts)
+
+	// The catalog is fetched once and rendered in every requested format
+	formatters.ToolVersion = version
+	opts.Source = source
+	for _, target := range targets {
+		// The filename template may place files in subdirectories of --output-dir
+		if outputDir != "" {
+			if err := os.MkdirAll(filepath.Dir(target.Path), 0o755); err != nil {
+				return fmt.Errorf("failed to create output directory: %w", err)
+			}
+		}
+		if err := writeTarget(target, opts, providers, instances); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writesDirectory reports whether a formatter writes several files instead of a single stream
+func writesDirectory(formatter formatters.Formatter) bool {
+	dirFormatter, ok := formatter.(formatters.DirectoryFormatter)
+	return ok && dirFormatter.WritesDirectory()
+}
+
+// writeTarget renders the catalog in the target format to its file, or to stdout
+func writeTarget(target output.Target, opts formatters.Options, providers []clevercloud.AddonProvider, instances []clevercloud.ProductInstance) error {
+	formatter := target.Format.New(opts)
+
+	// Formatters writing several files use --output as their directory, or
+	// the file name given by --filename-template, without its extension, in
+	// --output-dir
+	if writesDirectory(formatter) {
+		dir := target.Path
+		if outputDir != "" {
+			dir = target.Dir()
+		}
+
+		paths, err := formatter.(formatters.DirectoryFormatter).FormatDirectory(providers, instances, dir)
+		if err != nil {
+			return fmt.Errorf("failed to format %s output: %w", target.Format.Name, err)
+		}
+		fmt.Fprintf(os.Stderr, "Successfully generated %s with %d addon providers and %d application types\n",
+			strings.Join(paths, ", "), len(providers), len(instances))
+		return nil
+	}
 
 	// Determine output destination
-	var output *os.File
-	if outputFile == "" {
-		output = os.Stdout
+	var out *os.File
+	if target.Path == "" {
+		out = os.Stdout
 	} else {
-		output, err = os.Create(outputFile)
+		var err error
+		out, err = os.Create(target.Path)
 		if err != nil {
 			return fmt.Errorf("failed to create output file: %w", err)
 		}
-		defer output.Close()
+		defer out.Close()
 	}
 
 	// Generate output
-	err = formatter.Format(providers, instances, output)
-	if err != nil {
-		return fmt.Errorf("failed to format output: %w", err)
+	if err := formatter.Format(providers, instances, out); err != nil {
+		return fmt.Errorf("failed to format %s output: %w", target.Format.Name, err)
 	}
 
 	// Success message (only if writing to file)
-	if outputFile != "" {
+	if target.Path != "" {
+		if err := out.Close(); err != nil {
+			return fmt.Errorf("failed to write output file: %w", err)
+		}
 		fmt.Fprintf(os.Stderr, "Successfully generated %s with %d addon providers and %d application types\n",
-			outputFile, len(providers), len(instances))
+			target.Path, len(providers), len(instances))
 	}
 
 	return nil
 }
 
+// fetchCatalog fetches addon providers and product instances from the Clever
+// Cloud API. With --zone, the zone list is fetched too so that the zone can be
+// checked; failing to get it is not fatal since the catalog references zones.
+func fetchCatalog() ([]clevercloud.AddonProvider, []clevercloud.ProductInstance, []string, error) {
+	// Load configuration
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	// Create API client
+	clientOpts := []api.Option{
+		api.WithBaseURL(apiURL),
+		api.WithUserAgent("cc-plans-lister/" + version),
+		api.WithSecret(cfg.APISecret),
+		api.WithRetryPolicy(api.RetryPolicy{
+			MaxRetries: retries,
+			BaseDelay:  api.DefaultRetryPolicy.BaseDelay,
+			MaxWait:    retryMaxWait,
+		}),
+	}
+	if orgID != "" {
+		clientOpts = append(clientOpts, api.WithOrganisation(orgID))
+	}
+	if !noCache {
+		clientOpts = append(clientOpts, api.WithCache(api.CacheOptions{TTL: cacheTTL, Refresh: refreshCache}))
+	}
+	client := api.NewClient(cfg.APIToken, clientOpts...)
+
+	// Abort in-flight requests on Ctrl-C or when the deadline is reached
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if fetchTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, fetchTimeout)
+		defer cancel()
+	}
+
+	// Fetch addon providers and product instances concurrently
+	fmt.Fprintln(os.Stderr, "Fetching addon providers and application instances from Clever Cloud API...")
+	providers, instances, err := client.FetchCatalog(ctx)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, nil, nil, fmt.Errorf("%w after %s", err, fetchTimeout)
+		}
+		if errors.Is(err, context.Canceled) {
+			return nil, nil, nil, fmt.Errorf("interrupted: %w", err)
+		}
+		return nil, nil, nil, err
+	}
+
+	var zoneNames []string
+	if zone != "" {
+		zones, err := client.GetZones(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to fetch the zone list, --zone is checked against the zones of the catalog: %v\n", err)
+		}
+		for _, z := range zones {
+			zoneNames = append(zoneNames, z.Name)
+		}
+	}
+
+	return providers, instances, zoneNames, nil
+}
+
 func main() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
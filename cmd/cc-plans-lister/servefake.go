@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"cc-plans-lister/internal/fakeapi"
+)
+
+var (
+	fakeAddr       string
+	fakeProviders  string
+	fakeInstances  string
+	fakeZones      string
+	fakeLatency    time.Duration
+	fakeStatus     int
+	fakeFailCount  int
+	fakeRetryAfter time.Duration
+	fakeMalformed  bool
+	fakeETag       bool
+)
+
+// serveFakeCmd runs a local stand-in for the Clever Cloud product API
+var serveFakeCmd = &cobra.Command{
+	Use:    "serve-fake",
+	Short:  "Serve a fake Clever Cloud product API for tests and demos",
+	Hidden: true,
+	Args:   cobra.NoArgs,
+	RunE:   runServeFake,
+}
+
+func init() {
+	serveFakeCmd.Flags().StringVar(&fakeAddr, "addr", "127.0.0.1:8080", "Address to listen on")
+	serveFakeCmd.Flags().StringVar(&fakeProviders, "providers", "", "JSON file served on "+fakeapi.AddonProvidersPath+" (default: bundled fixture)")
+	serveFakeCmd.Flags().StringVar(&fakeInstances, "instances", "", "JSON file served on "+fakeapi.InstancesPath+" (default: bundled fixture)")
+	serveFakeCmd.Flags().StringVar(&fakeZones, "zones", "", "JSON file served on "+fakeapi.ZonesPath+" (default: bundled fixture)")
+	serveFakeCmd.Flags().DurationVar(&fakeLatency, "latency", 0, "Delay added to every response")
+	serveFakeCmd.Flags().IntVar(&fakeStatus, "status", 0, "HTTP status returned instead of the payload (e.g. 401, 429, 500)")
+	serveFakeCmd.Flags().IntVar(&fakeFailCount, "fail-count", 0, "Only fail the first N requests with --status (0: every request)")
+	serveFakeCmd.Flags().DurationVar(&fakeRetryAfter, "retry-after", 0, "Retry-After sent with failed responses")
+	serveFakeCmd.Flags().BoolVar(&fakeMalformed, "malformed", false, "Serve truncated JSON")
+	serveFakeCmd.Flags().BoolVar(&fakeETag, "etag", false, "Send ETags and answer conditional requests with 304")
+
+	rootCmd.AddCommand(serveFakeCmd)
+}
+
+func runServeFake(cmd *cobra.Command, args []string) error {
+	opts, err := fakeapi.LoadOptions(fakeProviders, fakeInstances, fakeZones)
+	if err != nil {
+		return err
+	}
+
+	opts.Latency = fakeLatency
+	opts.FailStatus = fakeStatus
+	opts.FailCount = fakeFailCount
+	opts.RetryAfter = fakeRetryAfter
+	opts.Malformed = fakeMalformed
+	opts.ETag = fakeETag
+
+	fmt.Fprintf(os.Stderr, "Serving fake Clever Cloud API on http://%s\n", fakeAddr)
+	return http.ListenAndServe(fakeAddr, fakeapi.New(opts))
+}
@@ -0,0 +1,220 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultCacheTTL is how long cached catalog payloads are served without contacting the API
+const DefaultCacheTTL = time.Hour
+
+// CacheOptions configures the on-disk response cache
+type CacheOptions struct {
+	// Dir is the cache directory; DefaultCacheDir is used when empty
+	Dir string
+	// TTL is how long entries are served without contacting the API. Older
+	// entries are revalidated with ETag/If-Modified-Since when available.
+	TTL time.Duration
+	// Refresh revalidates every entry with the API regardless of its age
+	Refresh bool
+}
+
+// WithCache stores fetched payloads on disk, keyed by URL and token identity
+func WithCache(cache CacheOptions) Option {
+	return func(o *options) {
+		o.cache = &cache
+	}
+}
+
+// DefaultCacheDir returns the cache directory under the user cache dir
+// ($XDG_CACHE_HOME or ~/.cache on Linux)
+func DefaultCacheDir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate user cache directory: %w", err)
+	}
+	return filepath.Join(base, "cc-plans-lister"), nil
+}
+
+// cacheEntry is a cached response as stored on disk
+type cacheEntry struct {
+	URL          string    `json:"url"`
+	StoredAt     time.Time `json:"stored_at"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	ContentType  string    `json:"content_type,omitempty"`
+	Body         []byte    `json:"body"`
+}
+
+// cacheTransport serves GET responses from disk and revalidates stale ones
+type cacheTransport struct {
+	next     http.RoundTripper
+	dir      string
+	ttl      time.Duration
+	refresh  bool
+	identity string // hash of the credentials, so tokens never share entries
+	now      func() time.Time
+}
+
+// newCacheTransport creates a cache transport for the given credentials
+func newCacheTransport(next http.RoundTripper, cache CacheOptions, token, secret string) (*cacheTransport, error) {
+	dir := cache.Dir
+	if dir == "" {
+		var err error
+		dir, err = DefaultCacheDir()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	sum := sha256.Sum256([]byte(token + "\x00" + secret))
+
+	return &cacheTransport{
+		next:     next,
+		dir:      dir,
+		ttl:      cache.TTL,
+		refresh:  cache.Refresh,
+		identity: hex.EncodeToString(sum[:]),
+		now:      time.Now,
+	}, nil
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return t.next.RoundTrip(req)
+	}
+
+	path := t.path(req)
+	entry := t.load(path)
+
+	if entry != nil && !t.refresh && t.now().Sub(entry.StoredAt) < t.ttl {
+		return entry.response(req), nil
+	}
+
+	if entry != nil {
+		req = req.Clone(req.Context())
+		if entry.ETag != "" {
+			req.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	res, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case res.StatusCode == http.StatusNotModified && entry != nil:
+		res.Body.Close()
+		entry.StoredAt = t.now()
+		t.store(path, entry)
+		return entry.response(req), nil
+
+	case res.StatusCode == http.StatusOK:
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		t.store(path, &cacheEntry{
+			URL:          req.URL.String(),
+			StoredAt:     t.now(),
+			ETag:         res.Header.Get("ETag"),
+			LastModified: res.Header.Get("Last-Modified"),
+			ContentType:  res.Header.Get("Content-Type"),
+			Body:         body,
+		})
+
+		res.Body = io.NopCloser(bytes.NewReader(body))
+		return res, nil
+
+	default:
+		return res, nil
+	}
+}
+
+// path returns the cache file for a request, keyed by URL and token identity
+func (t *cacheTransport) path(req *http.Request) string {
+	sum := sha256.Sum256([]byte(t.identity + " " + req.URL.String()))
+	return filepath.Join(t.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// load reads a cache entry; a missing or unreadable entry is treated as a miss
+func (t *cacheTransport) load(path string) *cacheEntry {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil
+	}
+
+	return &entry
+}
+
+// store writes a cache entry atomically. Failures are ignored: the cache is an
+// optimization and must never fail a request that succeeded.
+func (t *cacheTransport) store(path string, entry *cacheEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	if err := os.MkdirAll(t.dir, 0o700); err != nil {
+		return
+	}
+
+	tmp, err := os.CreateTemp(t.dir, ".entry-*")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return
+	}
+	if err := tmp.Close(); err != nil {
+		return
+	}
+
+	os.Rename(tmp.Name(), path)
+}
+
+// response builds an HTTP response serving the cached body
+func (e *cacheEntry) response(req *http.Request) *http.Response {
+	header := make(http.Header)
+	if e.ContentType != "" {
+		header.Set("Content-Type", e.ContentType)
+	}
+	if e.ETag != "" {
+		header.Set("ETag", e.ETag)
+	}
+
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
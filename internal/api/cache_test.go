@@ -0,0 +1,98 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cc-plans-lister/internal/fakeapi"
+)
+
+// conditionalRecorder records the conditional headers of requests sent to the fake API
+type conditionalRecorder struct {
+	fake        *fakeapi.Server
+	ifNoneMatch []string
+}
+
+func (r *conditionalRecorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.ifNoneMatch = append(r.ifNoneMatch, req.Header.Get("If-None-Match"))
+	r.fake.ServeHTTP(w, req)
+}
+
+func TestCacheServesFreshEntries(t *testing.T) {
+	fake := fakeapi.New(fakeapi.Options{})
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	cache := CacheOptions{Dir: t.TempDir(), TTL: DefaultCacheTTL}
+
+	first, err := NewClient("token", WithBaseURL(server.URL), WithCache(cache)).GetAddonProviders(context.Background())
+	require.NoError(t, err)
+
+	second, err := NewClient("token", WithBaseURL(server.URL), WithCache(cache)).GetAddonProviders(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, first, second)
+	assert.Equal(t, 1, fake.Requests())
+
+	// Another token never reads entries stored for the first one
+	_, err = NewClient("other", WithBaseURL(server.URL), WithCache(cache)).GetAddonProviders(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, fake.Requests())
+}
+
+func TestCacheRevalidatesStaleEntries(t *testing.T) {
+	recorder := &conditionalRecorder{fake: fakeapi.New(fakeapi.Options{ETag: true})}
+	server := httptest.NewServer(recorder)
+	defer server.Close()
+
+	// A zero TTL makes every entry stale
+	cache := CacheOptions{Dir: t.TempDir()}
+	client := NewClient("token", WithBaseURL(server.URL), WithCache(cache))
+
+	first, err := client.GetProductInstances(context.Background())
+	require.NoError(t, err)
+
+	second, err := client.GetProductInstances(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, first, second)
+	require.Len(t, recorder.ifNoneMatch, 2)
+	assert.Empty(t, recorder.ifNoneMatch[0])
+	assert.NotEmpty(t, recorder.ifNoneMatch[1], "stale entries should be revalidated with their ETag")
+}
+
+func TestCacheRefresh(t *testing.T) {
+	fake := fakeapi.New(fakeapi.Options{})
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	dir := t.TempDir()
+
+	_, err := NewClient("token", WithBaseURL(server.URL), WithCache(CacheOptions{Dir: dir, TTL: DefaultCacheTTL})).GetAddonProviders(context.Background())
+	require.NoError(t, err)
+
+	_, err = NewClient("token", WithBaseURL(server.URL), WithCache(CacheOptions{Dir: dir, TTL: DefaultCacheTTL, Refresh: true})).GetAddonProviders(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, fake.Requests())
+}
+
+func TestCacheSkipsErrors(t *testing.T) {
+	fake := fakeapi.New(fakeapi.Options{FailStatus: http.StatusInternalServerError, FailCount: 1})
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	cache := CacheOptions{Dir: t.TempDir(), TTL: DefaultCacheTTL}
+
+	_, err := NewClient("token", WithBaseURL(server.URL), WithCache(cache)).GetAddonProviders(context.Background())
+	assert.Error(t, err)
+
+	_, err = NewClient("token", WithBaseURL(server.URL), WithCache(cache)).GetAddonProviders(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, fake.Requests())
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"cc-plans-lister/pkg/clevercloud"
+)
+
+// EndpointError reports which catalog endpoint failed
+type EndpointError struct {
+	Endpoint string
+	Err      error
+}
+
+// Error implements the error interface
+func (e *EndpointError) Error() string {
+	switch {
+	case errors.Is(e.Err, context.DeadlineExceeded):
+		return fmt.Sprintf("fetching %s timed out", e.Endpoint)
+	case errors.Is(e.Err, context.Canceled):
+		return fmt.Sprintf("fetching %s was canceled", e.Endpoint)
+	default:
+		return fmt.Sprintf("failed to fetch %s: %v", e.Endpoint, e.Err)
+	}
+}
+
+// Unwrap returns the underlying error
+func (e *EndpointError) Unwrap() error {
+	return e.Err
+}
+
+// FetchCatalog fetches addon providers and product instances concurrently.
+// The first failure cancels the other request, and the returned
+// *EndpointError names the endpoint that failed. Zones are not part of the
+// catalog: the instances endpoint does not tell in which zones each type is
+// available, so they are only fetched by GetZones when needed.
+func (c *Client) FetchCatalog(ctx context.Context) ([]clevercloud.AddonProvider, []clevercloud.ProductInstance, error) {
+	fetchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		providers []clevercloud.AddonProvider
+		instances []clevercloud.ProductInstance
+		wg        sync.WaitGroup
+		once      sync.Once
+		firstErr  error
+	)
+
+	fetch := func(endpoint string, get func(context.Context) error) {
+		defer wg.Done()
+
+		err := get(fetchCtx)
+		if err == nil {
+			return
+		}
+
+		once.Do(func() {
+			// The SDK does not always wrap context errors, so report the
+			// parent context error when it is the actual cause
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				err = ctxErr
+			}
+			firstErr = &EndpointError{Endpoint: endpoint, Err: err}
+			cancel()
+		})
+	}
+
+	wg.Add(2)
+
+	go fetch("addon providers", func(ctx context.Context) (err error) {
+		providers, err = c.GetAddonProviders(ctx)
+		return err
+	})
+
+	go fetch("product instances", func(ctx context.Context) (err error) {
+		instances, err = c.GetProductInstances(ctx)
+		return err
+	})
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, nil, firstErr
+	}
+
+	return providers, instances, nil
+}
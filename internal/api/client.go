@@ -2,33 +2,162 @@ package api
 
 import (
 	"context"
-	"os"
+	"net/http"
+	"net/url"
 	"sort"
+	"time"
 
 	"go.clever-cloud.dev/client"
 
 	"cc-plans-lister/pkg/clevercloud"
 )
 
+const (
+	// DefaultBaseURL is the public Clever Cloud API endpoint
+	DefaultBaseURL = "https://api.clever-cloud.com"
+	// DefaultUserAgent is sent when no user agent is configured
+	DefaultUserAgent = "cc-plans-lister"
+)
+
 // Client wraps the Clever Cloud API client
 type Client struct {
-	cc *client.Client
+	cc      *client.Client
+	baseURL string
+	orgID   string
+}
+
+// Option configures a Client
+type Option func(*options)
+
+type options struct {
+	baseURL    string
+	httpClient *http.Client
+	transport  http.RoundTripper
+	timeout    time.Duration
+	userAgent  string
+	secret     string
+	retry      RetryPolicy
+	cache      *CacheOptions
+	orgID      string
+}
+
+// WithBaseURL sets the API endpoint, e.g. a proxy, a staging API or a local stand-in
+func WithBaseURL(baseURL string) Option {
+	return func(o *options) {
+		o.baseURL = baseURL
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for requests. The client is copied,
+// so the caller's value is never modified.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(o *options) {
+		o.httpClient = httpClient
+	}
+}
+
+// WithTransport sets the transport used for requests, replacing the one of the HTTP client
+func WithTransport(transport http.RoundTripper) Option {
+	return func(o *options) {
+		o.transport = transport
+	}
+}
+
+// WithTimeout sets a timeout for each HTTP request
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
 }
 
-// NewClient creates a new API client with the provided token
-func NewClient(token string) *Client {
-	// Set up environment variables for OAuth authentication
-	os.Setenv("CLEVER_TOKEN", token)
+// WithUserAgent sets the User-Agent header sent with every request
+func WithUserAgent(userAgent string) Option {
+	return func(o *options) {
+		o.userAgent = userAgent
+	}
+}
 
-	// Create client with auto OAuth configuration
-	cc := client.New(client.WithAutoOauthConfig())
+// WithSecret sets the OAuth secret associated with the token
+func WithSecret(secret string) Option {
+	return func(o *options) {
+		o.secret = secret
+	}
+}
 
-	return &Client{cc: cc}
+// WithOrganisation scopes the catalog to an organisation, so that its private
+// providers, negotiated prices and enabled instance types are listed
+func WithOrganisation(orgID string) Option {
+	return func(o *options) {
+		o.orgID = orgID
+	}
+}
+
+// NewClient creates a new API client authenticated with the provided token.
+// Credentials are handed to the underlying client directly, so several
+// clients with different tokens can be used in the same process.
+func NewClient(token string, opts ...Option) *Client {
+	o := options{
+		baseURL:   DefaultBaseURL,
+		userAgent: DefaultUserAgent,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	httpClient := &http.Client{}
+	if o.httpClient != nil {
+		copied := *o.httpClient
+		httpClient = &copied
+	}
+	if o.transport != nil {
+		httpClient.Transport = o.transport
+	}
+	if o.timeout > 0 {
+		httpClient.Timeout = o.timeout
+	}
+
+	transport := httpClient.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	if o.retry.MaxRetries > 0 {
+		transport = &retryTransport{next: transport, policy: o.retry}
+	}
+	if o.cache != nil {
+		// Without a usable cache directory requests simply go to the API
+		if cache, err := newCacheTransport(transport, *o.cache, token, o.secret); err == nil {
+			transport = cache
+		}
+	}
+	httpClient.Transport = &userAgentTransport{next: transport, userAgent: o.userAgent}
+
+	cc := client.New(
+		client.WithEndpoint(o.baseURL),
+		client.WithHTTPClient(httpClient),
+		client.WithUserOauthConfig(token, o.secret),
+	)
+
+	return &Client{cc: cc, baseURL: o.baseURL, orgID: o.orgID}
+}
+
+// BaseURL returns the API endpoint the client talks to
+func (c *Client) BaseURL() string {
+	return c.baseURL
+}
+
+// Organisation returns the organisation the catalog is scoped to, if any
+func (c *Client) Organisation() string {
+	return c.orgID
 }
 
 // GetAddonProviders fetches all addon providers from the Clever Cloud API
 func (c *Client) GetAddonProviders(ctx context.Context) ([]clevercloud.AddonProvider, error) {
-	addonRes := client.Get[[]clevercloud.AddonProvider](ctx, c.cc, "/v2/products/addonproviders")
+	path := "/v2/products/addonproviders"
+	if c.orgID != "" {
+		path += "?orgaId=" + url.QueryEscape(c.orgID)
+	}
+
+	addonRes := client.Get[[]clevercloud.AddonProvider](ctx, c.cc, path)
 
 	if addonRes.HasError() {
 		return nil, addonRes.Error()
@@ -46,7 +175,12 @@ func (c *Client) GetAddonProviders(ctx context.Context) ([]clevercloud.AddonProv
 
 // GetProductInstances fetches all application instances from the Clever Cloud API
 func (c *Client) GetProductInstances(ctx context.Context) ([]clevercloud.ProductInstance, error) {
-	instanceRes := client.Get[[]clevercloud.ProductInstance](ctx, c.cc, "/v2/products/instances")
+	path := "/v2/products/instances"
+	if c.orgID != "" {
+		path += "?for=" + url.QueryEscape(c.orgID)
+	}
+
+	instanceRes := client.Get[[]clevercloud.ProductInstance](ctx, c.cc, path)
 
 	if instanceRes.HasError() {
 		return nil, instanceRes.Error()
@@ -61,3 +195,34 @@ func (c *Client) GetProductInstances(ctx context.Context) ([]clevercloud.Product
 
 	return instances, nil
 }
+
+// GetZones fetches the deployment zones from the Clever Cloud API
+func (c *Client) GetZones(ctx context.Context) ([]clevercloud.Zone, error) {
+	zoneRes := client.Get[[]clevercloud.Zone](ctx, c.cc, "/v4/products/zones")
+
+	if zoneRes.HasError() {
+		return nil, zoneRes.Error()
+	}
+
+	zones := *zoneRes.Payload()
+
+	// Sort zones by name for consistent output
+	sort.Slice(zones, func(i, j int) bool {
+		return zones[i].Name < zones[j].Name
+	})
+
+	return zones, nil
+}
+
+// userAgentTransport sets the User-Agent header on outgoing requests
+type userAgentTransport struct {
+	next      http.RoundTripper
+	userAgent string
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("User-Agent", t.userAgent)
+	return t.next.RoundTrip(req)
+}
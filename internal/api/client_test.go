@@ -1,19 +1,192 @@
 package api
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"cc-plans-lister/internal/fakeapi"
 )
 
 func TestNewClient(t *testing.T) {
+	t.Setenv("CLEVER_TOKEN", "")
+	t.Setenv("CLEVER_SECRET", "")
+
 	token := "test_token_123"
-	client := NewClient(token)
+	client := NewClient(token, WithSecret("test_secret_456"))
 
 	require.NotNil(t, client)
 	require.NotNil(t, client.cc)
+	assert.Equal(t, DefaultBaseURL, client.BaseURL())
+
+	// Credentials are never leaked into the process environment
+	assert.Empty(t, os.Getenv("CLEVER_TOKEN"))
+	assert.Empty(t, os.Getenv("CLEVER_SECRET"))
+}
+
+func TestGetCatalog(t *testing.T) {
+	var userAgent string
+	var mu sync.Mutex
+	fake := fakeapi.New(fakeapi.Options{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		userAgent = r.Header.Get("User-Agent")
+		mu.Unlock()
+		fake.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	client := NewClient("token", WithBaseURL(server.URL), WithUserAgent("cc-plans-lister/test"))
+	assert.Equal(t, server.URL, client.BaseURL())
+
+	providers, err := client.GetAddonProviders(context.Background())
+	require.NoError(t, err)
+	require.NotEmpty(t, providers)
+	for i := 1; i < len(providers); i++ {
+		assert.Less(t, providers[i-1].ID, providers[i].ID, "providers should be sorted by ID")
+	}
+	mu.Lock()
+	assert.Equal(t, "cc-plans-lister/test", userAgent)
+	mu.Unlock()
+
+	instances, err := client.GetProductInstances(context.Background())
+	require.NoError(t, err)
+	require.NotEmpty(t, instances)
+	for i := 1; i < len(instances); i++ {
+		assert.Less(t, instances[i-1].Type, instances[i].Type, "instances should be sorted by type")
+	}
+}
+
+func TestGetCatalogErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		opts fakeapi.Options
+	}{
+		{"unauthorized", fakeapi.Options{FailStatus: http.StatusUnauthorized}},
+		{"server error", fakeapi.Options{FailStatus: http.StatusInternalServerError}},
+		{"malformed", fakeapi.Options{Malformed: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(fakeapi.New(tt.opts))
+			defer server.Close()
+
+			client := NewClient("token", WithBaseURL(server.URL))
+
+			_, err := client.GetAddonProviders(context.Background())
+			assert.Error(t, err)
+
+			_, err = client.GetProductInstances(context.Background())
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestClientTimeout(t *testing.T) {
+	server := httptest.NewServer(fakeapi.New(fakeapi.Options{Latency: time.Second}))
+	defer server.Close()
+
+	httpClient := &http.Client{}
+	client := NewClient("token", WithBaseURL(server.URL), WithHTTPClient(httpClient), WithTimeout(50*time.Millisecond))
+
+	_, err := client.GetAddonProviders(context.Background())
+	assert.Error(t, err)
+
+	// The caller's HTTP client is left untouched
+	assert.Zero(t, httpClient.Timeout)
+	assert.Nil(t, httpClient.Transport)
+}
+
+func TestFetchCatalog(t *testing.T) {
+	server := httptest.NewServer(fakeapi.New(fakeapi.Options{}))
+	defer server.Close()
+
+	client := NewClient("token", WithBaseURL(server.URL))
+
+	providers, instances, err := client.FetchCatalog(context.Background())
+	require.NoError(t, err)
+	assert.NotEmpty(t, providers)
+	assert.NotEmpty(t, instances)
+}
+
+func TestFetchCatalogEndpointError(t *testing.T) {
+	fake := fakeapi.New(fakeapi.Options{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == fakeapi.InstancesPath {
+			http.Error(w, "boom", http.StatusInternalServerError)
+			return
+		}
+		fake.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	client := NewClient("token", WithBaseURL(server.URL))
+
+	_, _, err := client.FetchCatalog(context.Background())
+	var endpointErr *EndpointError
+	require.ErrorAs(t, err, &endpointErr)
+	assert.Equal(t, "product instances", endpointErr.Endpoint)
+	assert.Contains(t, err.Error(), "failed to fetch product instances")
+}
+
+func TestFetchCatalogDeadline(t *testing.T) {
+	server := httptest.NewServer(fakeapi.New(fakeapi.Options{Latency: time.Second}))
+	defer server.Close()
+
+	client := NewClient("token", WithBaseURL(server.URL))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, _, err := client.FetchCatalog(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "timed out")
+	assert.Less(t, time.Since(start), time.Second, "in-flight requests should be aborted")
+}
+
+func TestGetZones(t *testing.T) {
+	server := httptest.NewServer(fakeapi.New(fakeapi.Options{}))
+	defer server.Close()
+
+	client := NewClient("token", WithBaseURL(server.URL))
+
+	zones, err := client.GetZones(context.Background())
+	require.NoError(t, err)
+	require.NotEmpty(t, zones)
+	for i := 1; i < len(zones); i++ {
+		assert.Less(t, zones[i-1].Name, zones[i].Name, "zones should be sorted by name")
+	}
 }
 
-// Note: Integration tests for GetAddonProviders and GetProductInstances
-// would require a valid API token and network access, so they are not
-// included in unit tests. These should be tested separately as integration tests.
+func TestOrganisationScopedCatalog(t *testing.T) {
+	var queries []string
+	var mu sync.Mutex
+	fake := fakeapi.New(fakeapi.Options{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.Path+"?"+r.URL.RawQuery)
+		mu.Unlock()
+		fake.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	client := NewClient("token", WithBaseURL(server.URL), WithOrganisation("orga_123"))
+	assert.Equal(t, "orga_123", client.Organisation())
+
+	_, _, err := client.FetchCatalog(context.Background())
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{
+		"/v2/products/addonproviders?orgaId=orga_123",
+		"/v2/products/instances?for=orga_123",
+	}, queries)
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cc-plans-lister/internal/fakeapi"
+)
+
+var testRetryPolicy = RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxWait: 2 * time.Second}
+
+func TestRetryTransientFailures(t *testing.T) {
+	for _, status := range []int{http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout} {
+		t.Run(http.StatusText(status), func(t *testing.T) {
+			fake := fakeapi.New(fakeapi.Options{FailStatus: status, FailCount: 2})
+			server := httptest.NewServer(fake)
+			defer server.Close()
+
+			client := NewClient("token", WithBaseURL(server.URL), WithRetryPolicy(testRetryPolicy))
+
+			providers, err := client.GetAddonProviders(context.Background())
+			require.NoError(t, err)
+			assert.NotEmpty(t, providers)
+			assert.Equal(t, 3, fake.Requests())
+		})
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	fake := fakeapi.New(fakeapi.Options{FailStatus: http.StatusServiceUnavailable})
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	client := NewClient("token", WithBaseURL(server.URL), WithRetryPolicy(testRetryPolicy))
+
+	_, err := client.GetAddonProviders(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, 4, fake.Requests())
+}
+
+func TestRetryNotRetryable(t *testing.T) {
+	fake := fakeapi.New(fakeapi.Options{FailStatus: http.StatusUnauthorized})
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	client := NewClient("token", WithBaseURL(server.URL), WithRetryPolicy(testRetryPolicy))
+
+	_, err := client.GetAddonProviders(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, 1, fake.Requests())
+}
+
+func TestRetryAfter(t *testing.T) {
+	fake := fakeapi.New(fakeapi.Options{FailStatus: http.StatusTooManyRequests, FailCount: 1, RetryAfter: time.Second})
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	client := NewClient("token", WithBaseURL(server.URL), WithRetryPolicy(testRetryPolicy))
+
+	start := time.Now()
+	_, err := client.GetAddonProviders(context.Background())
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+	assert.Equal(t, 2, fake.Requests())
+
+	// A Retry-After beyond MaxWait is not retried
+	fake = fakeapi.New(fakeapi.Options{FailStatus: http.StatusTooManyRequests, FailCount: 1, RetryAfter: time.Minute})
+	server2 := httptest.NewServer(fake)
+	defer server2.Close()
+
+	client = NewClient("token", WithBaseURL(server2.URL), WithRetryPolicy(testRetryPolicy))
+
+	_, err = client.GetAddonProviders(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, 1, fake.Requests())
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{"", 0, false},
+		{"5", 5 * time.Second, true},
+		{"-1", 0, false},
+		{"Mon, 01 Jan 2024 12:00:10 GMT", 10 * time.Second, true},
+		{"Mon, 01 Jan 2024 11:00:00 GMT", 0, true},
+		{"soon", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			wait, ok := parseRetryAfter(tt.value, now)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, wait)
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	transport := &retryTransport{policy: RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxWait: time.Second}}
+
+	for attempt, max := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second} {
+		delay := transport.backoff(attempt)
+		assert.GreaterOrEqual(t, delay, max/2)
+		assert.LessOrEqual(t, delay, max)
+	}
+
+	// Large attempts must not overflow into a zero or negative delay
+	assert.Greater(t, transport.backoff(70), time.Duration(0))
+}
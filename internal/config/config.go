@@ -3,11 +3,14 @@ package config
 import (
 	"fmt"
 	"os"
+
+	"cc-plans-lister/internal/formatters"
 )
 
 // Config holds application configuration
 type Config struct {
 	APIToken     string
+	APISecret    string // OAuth secret of the token, when it has one
 	OutputFormat string
 	OutputFile   string
 }
@@ -21,18 +24,14 @@ func LoadConfig() (*Config, error) {
 
 	return &Config{
 		APIToken:     token,
-		OutputFormat: "markdown", // default format
-		OutputFile:   "",         // default to stdout
+		APISecret:    os.Getenv("CLEVER_SECRET"),
+		OutputFormat: formatters.DefaultFormat,
+		OutputFile:   "", // default to stdout
 	}, nil
 }
 
-// ValidateOutputFormat checks if the provided format is supported
+// ValidateOutputFormat checks if the provided format (or alias) is registered
 func ValidateOutputFormat(format string) bool {
-	validFormats := map[string]bool{
-		"markdown": true,
-		"txt":      true,
-		"csv":      true,
-		"pdf":      true,
-	}
-	return validFormats[format]
+	_, ok := formatters.Lookup(format)
+	return ok
 }
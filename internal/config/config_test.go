@@ -53,6 +53,15 @@ func TestLoadConfig(t *testing.T) {
 	}
 }
 
+func TestLoadConfigSecret(t *testing.T) {
+	t.Setenv("CLEVER_API_TOKEN", "valid_token_123")
+	t.Setenv("CLEVER_SECRET", "secret_456")
+
+	cfg, err := LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "secret_456", cfg.APISecret)
+}
+
 func TestValidateOutputFormat(t *testing.T) {
 	tests := []struct {
 		format string
@@ -62,7 +71,8 @@ func TestValidateOutputFormat(t *testing.T) {
 		{"txt", true},
 		{"csv", true},
 		{"pdf", true},
-		{"json", false},
+		{"json", true},
+		{"md", true}, // alias
 		{"xml", false},
 		{"", false},
 		{"MARKDOWN", false}, // case sensitive
@@ -0,0 +1,237 @@
+package diff
+
+import (
+	"sort"
+
+	"cc-plans-lister/internal/snapshot"
+	"cc-plans-lister/pkg/clevercloud"
+)
+
+// Section identifies the part of the catalog a change belongs to
+type Section string
+
+const (
+	SectionProviders Section = "providers"
+	SectionPlans     Section = "plans"
+	SectionInstances Section = "instances"
+	SectionFlavors   Section = "flavors"
+)
+
+// sections lists the sections in report order
+var sections = []Section{SectionProviders, SectionPlans, SectionInstances, SectionFlavors}
+
+// Kind describes what happened to a catalog item
+type Kind string
+
+const (
+	KindAdded   Kind = "added"
+	KindRemoved Kind = "removed"
+	KindRenamed Kind = "renamed"
+	KindChanged Kind = "changed"
+)
+
+// Change is a single difference between two catalogs
+type Change struct {
+	Section Section `json:"section"`
+	Kind    Kind    `json:"kind"`
+	Item    string  `json:"item"`            // e.g. "redis", "redis/small", "node/nano"
+	Field   string  `json:"field,omitempty"` // changed field for renamed/changed items
+	Old     any     `json:"old"`
+	New     any     `json:"new"`
+}
+
+// Report holds every change between two catalogs
+type Report struct {
+	Old     *snapshot.Snapshot `json:"-"`
+	New     *snapshot.Snapshot `json:"-"`
+	Changes []Change           `json:"changes"`
+}
+
+// Compare returns the changes needed to go from the old catalog to the new one
+func Compare(oldSnap, newSnap *snapshot.Snapshot) *Report {
+	report := &Report{Old: oldSnap, New: newSnap, Changes: []Change{}}
+
+	report.compareProviders(oldSnap.Providers, newSnap.Providers)
+	report.compareInstances(oldSnap.Instances, newSnap.Instances)
+
+	// Sort changes for consistent output, keeping the section order
+	order := make(map[Section]int, len(sections))
+	for i, section := range sections {
+		order[section] = i
+	}
+	sort.SliceStable(report.Changes, func(i, j int) bool {
+		a, b := report.Changes[i], report.Changes[j]
+		if a.Section != b.Section {
+			return order[a.Section] < order[b.Section]
+		}
+		return a.Item < b.Item
+	})
+
+	return report
+}
+
+// Empty reports whether the two catalogs are identical for the compared fields
+func (r *Report) Empty() bool {
+	return len(r.Changes) == 0
+}
+
+// ChangesIn returns the changes belonging to the given section
+func (r *Report) ChangesIn(section Section) []Change {
+	var changes []Change
+	for _, change := range r.Changes {
+		if change.Section == section {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+func (r *Report) add(section Section, kind Kind, item, field string, oldValue, newValue any) {
+	r.Changes = append(r.Changes, Change{
+		Section: section,
+		Kind:    kind,
+		Item:    item,
+		Field:   field,
+		Old:     oldValue,
+		New:     newValue,
+	})
+}
+
+func (r *Report) compareProviders(oldProviders, newProviders []clevercloud.AddonProvider) {
+	oldByID := make(map[string]clevercloud.AddonProvider, len(oldProviders))
+	for _, provider := range oldProviders {
+		oldByID[provider.ID] = provider
+	}
+	newByID := make(map[string]clevercloud.AddonProvider, len(newProviders))
+	for _, provider := range newProviders {
+		newByID[provider.ID] = provider
+	}
+
+	for id, oldProvider := range oldByID {
+		if _, ok := newByID[id]; !ok {
+			r.add(SectionProviders, KindRemoved, id, "", oldProvider.Name, nil)
+		}
+	}
+
+	for id, newProvider := range newByID {
+		oldProvider, ok := oldByID[id]
+		if !ok {
+			r.add(SectionProviders, KindAdded, id, "", nil, newProvider.Name)
+			continue
+		}
+
+		if oldProvider.Name != newProvider.Name {
+			r.add(SectionProviders, KindRenamed, id, "name", oldProvider.Name, newProvider.Name)
+		}
+		r.comparePlans(id, oldProvider.Plans, newProvider.Plans)
+	}
+}
+
+func (r *Report) comparePlans(providerID string, oldPlans, newPlans []clevercloud.AddonPlan) {
+	oldByID := make(map[string]clevercloud.AddonPlan, len(oldPlans))
+	for _, plan := range oldPlans {
+		oldByID[plan.ID] = plan
+	}
+	newByID := make(map[string]clevercloud.AddonPlan, len(newPlans))
+	for _, plan := range newPlans {
+		newByID[plan.ID] = plan
+	}
+
+	for id, oldPlan := range oldByID {
+		if _, ok := newByID[id]; !ok {
+			r.add(SectionPlans, KindRemoved, providerID+"/"+oldPlan.Slug, "", oldPlan.Name, nil)
+		}
+	}
+
+	for id, newPlan := range newByID {
+		oldPlan, ok := oldByID[id]
+		if !ok {
+			r.add(SectionPlans, KindAdded, providerID+"/"+newPlan.Slug, "", nil, newPlan.Name)
+			continue
+		}
+
+		if oldPlan.Name != newPlan.Name {
+			r.add(SectionPlans, KindRenamed, providerID+"/"+newPlan.Slug, "name", oldPlan.Name, newPlan.Name)
+		}
+		if oldPlan.Price != newPlan.Price {
+			r.add(SectionPlans, KindChanged, providerID+"/"+newPlan.Slug, "price", oldPlan.Price, newPlan.Price)
+		}
+	}
+}
+
+func (r *Report) compareInstances(oldInstances, newInstances []clevercloud.ProductInstance) {
+	oldByType := make(map[string]clevercloud.ProductInstance, len(oldInstances))
+	for _, instance := range oldInstances {
+		oldByType[instance.Type] = instance
+	}
+	newByType := make(map[string]clevercloud.ProductInstance, len(newInstances))
+	for _, instance := range newInstances {
+		newByType[instance.Type] = instance
+	}
+
+	for instanceType, oldInstance := range oldByType {
+		if _, ok := newByType[instanceType]; !ok {
+			r.add(SectionInstances, KindRemoved, instanceType, "", oldInstance.Name, nil)
+		}
+	}
+
+	for instanceType, newInstance := range newByType {
+		oldInstance, ok := oldByType[instanceType]
+		if !ok {
+			r.add(SectionInstances, KindAdded, instanceType, "", nil, newInstance.Name)
+			continue
+		}
+
+		if oldInstance.Name != newInstance.Name {
+			r.add(SectionInstances, KindRenamed, instanceType, "name", oldInstance.Name, newInstance.Name)
+		}
+		if oldInstance.Enabled != newInstance.Enabled {
+			r.add(SectionInstances, KindChanged, instanceType, "enabled", oldInstance.Enabled, newInstance.Enabled)
+		}
+		if oldInstance.ComingSoon != newInstance.ComingSoon {
+			r.add(SectionInstances, KindChanged, instanceType, "comingSoon", oldInstance.ComingSoon, newInstance.ComingSoon)
+		}
+		if oldInstance.DefaultFlavor.Name != newInstance.DefaultFlavor.Name {
+			r.add(SectionInstances, KindChanged, instanceType, "defaultFlavor", oldInstance.DefaultFlavor.Name, newInstance.DefaultFlavor.Name)
+		}
+		r.compareFlavors(instanceType, oldInstance.Flavors, newInstance.Flavors)
+	}
+}
+
+func (r *Report) compareFlavors(instanceType string, oldFlavors, newFlavors []clevercloud.Flavor) {
+	// Flavors are matched on their slug so that a changed display name is
+	// reported as a rename rather than as a removal plus an addition
+	oldBySlug := make(map[string]clevercloud.Flavor, len(oldFlavors))
+	for _, flavor := range oldFlavors {
+		oldBySlug[flavor.EffectiveSlug()] = flavor
+	}
+	newBySlug := make(map[string]clevercloud.Flavor, len(newFlavors))
+	for _, flavor := range newFlavors {
+		newBySlug[flavor.EffectiveSlug()] = flavor
+	}
+
+	for slug, oldFlavor := range oldBySlug {
+		if _, ok := newBySlug[slug]; !ok {
+			r.add(SectionFlavors, KindRemoved, instanceType+"/"+slug, "", oldFlavor.Name, nil)
+		}
+	}
+
+	for slug, newFlavor := range newBySlug {
+		item := instanceType + "/" + slug
+		oldFlavor, ok := oldBySlug[slug]
+		if !ok {
+			r.add(SectionFlavors, KindAdded, item, "", nil, newFlavor.Name)
+			continue
+		}
+
+		if oldFlavor.Name != newFlavor.Name {
+			r.add(SectionFlavors, KindRenamed, item, "name", oldFlavor.Name, newFlavor.Name)
+		}
+		if oldFlavor.Price != newFlavor.Price {
+			r.add(SectionFlavors, KindChanged, item, "price", oldFlavor.Price, newFlavor.Price)
+		}
+		if oldFlavor.Available != newFlavor.Available {
+			r.add(SectionFlavors, KindChanged, item, "available", oldFlavor.Available, newFlavor.Available)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cc-plans-lister/internal/snapshot"
+	"cc-plans-lister/pkg/clevercloud"
+	"cc-plans-lister/test/fixtures"
+)
+
+func TestCompareIdentical(t *testing.T) {
+	oldSnap := snapshot.New(fixtures.TestAddonProviders(), fixtures.TestProductInstances())
+	newSnap := snapshot.New(fixtures.TestAddonProviders(), fixtures.TestProductInstances())
+
+	report := Compare(oldSnap, newSnap)
+	assert.True(t, report.Empty())
+}
+
+func TestCompare(t *testing.T) {
+	oldSnap := snapshot.New(fixtures.TestAddonProviders(), fixtures.TestProductInstances())
+
+	providers := fixtures.TestAddonProviders()
+	instances := fixtures.TestProductInstances()
+
+	// Remove redis, rename a postgresql plan and add a new one
+	providers = providers[1:]
+	providers[0].Plans[0].Name = "Development PostgreSQL"
+	providers[0].Plans[1].Price = 25
+	providers[0].Plans = append(providers[0].Plans, clevercloud.AddonPlan{ID: "pg_xl", Name: "XL PostgreSQL", Slug: "xl"})
+	providers = append(providers, clevercloud.AddonProvider{ID: "mysql", Name: "MySQL"})
+
+	// Raise a node price, make a flavor unavailable and change the default flavor
+	instances[0].Flavors[0].Price = 0.03
+	instances[0].Flavors[1].Available = false
+	instances[0].DefaultFlavor = clevercloud.Flavor{Name: "small"}
+
+	// Rename python, disable it and rename and drop its only flavor for another one
+	instances[1].Name = "Python 3"
+	instances[1].Enabled = false
+	instances[1].Flavors = []clevercloud.Flavor{{Name: "medium", Slug: "medium", Price: 0.08}}
+
+	report := Compare(oldSnap, snapshot.New(providers, instances))
+
+	expected := []Change{
+		{Section: SectionProviders, Kind: KindAdded, Item: "mysql", New: "MySQL"},
+		{Section: SectionProviders, Kind: KindRemoved, Item: "redis", Old: "Redis"},
+		{Section: SectionPlans, Kind: KindRenamed, Item: "postgresql/dev", Field: "name", Old: "Dev PostgreSQL", New: "Development PostgreSQL"},
+		{Section: SectionPlans, Kind: KindChanged, Item: "postgresql/prod", Field: "price", Old: 20.0, New: 25.0},
+		{Section: SectionPlans, Kind: KindAdded, Item: "postgresql/xl", New: "XL PostgreSQL"},
+		{Section: SectionInstances, Kind: KindChanged, Item: "node", Field: "defaultFlavor", Old: "nano", New: "small"},
+		{Section: SectionInstances, Kind: KindRenamed, Item: "python", Field: "name", Old: "Python", New: "Python 3"},
+		{Section: SectionInstances, Kind: KindChanged, Item: "python", Field: "enabled", Old: true, New: false},
+		{Section: SectionFlavors, Kind: KindChanged, Item: "node/nano", Field: "price", Old: 0.02, New: 0.03},
+		{Section: SectionFlavors, Kind: KindChanged, Item: "node/small", Field: "available", Old: true, New: false},
+		{Section: SectionFlavors, Kind: KindAdded, Item: "python/medium", New: "medium"},
+		{Section: SectionFlavors, Kind: KindRemoved, Item: "python/small", Old: "small"},
+	}
+	assert.Equal(t, expected, report.Changes)
+}
+
+func TestRender(t *testing.T) {
+	instances := fixtures.TestProductInstances()
+	instances[0].Flavors[0].Price = 0.03
+
+	report := Compare(
+		snapshot.New(fixtures.TestAddonProviders(), fixtures.TestProductInstances()),
+		snapshot.New(fixtures.TestAddonProviders(), instances),
+	)
+
+	var buf bytes.Buffer
+	require.NoError(t, Render(report, "text", &buf))
+	assert.Contains(t, buf.String(), "FLAVORS")
+	assert.Contains(t, buf.String(), "~ node/nano price 0.02€ -> 0.03€ (+50.0%)")
+
+	buf.Reset()
+	require.NoError(t, Render(report, "markdown", &buf))
+	assert.Contains(t, buf.String(), "## Flavors")
+	assert.Contains(t, buf.String(), "| changed | `node/nano` | price 0.02€ -> 0.03€ (+50.0%) |")
+
+	buf.Reset()
+	require.NoError(t, Render(report, "json", &buf))
+	var doc struct {
+		Changes []Change `json:"changes"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+	require.Len(t, doc.Changes, 1)
+	assert.Equal(t, "price", doc.Changes[0].Field)
+
+	assert.Error(t, Render(report, "pdf", &buf))
+}
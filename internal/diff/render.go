@@ -0,0 +1,154 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// sectionTitles holds the human-readable title of each section
+var sectionTitles = map[Section]string{
+	SectionProviders: "Addon Providers",
+	SectionPlans:     "Addon Plans",
+	SectionInstances: "Instance Types",
+	SectionFlavors:   "Flavors",
+}
+
+// ValidateFormat checks if the provided diff output format is supported
+func ValidateFormat(format string) bool {
+	switch format {
+	case "text", "markdown", "json":
+		return true
+	default:
+		return false
+	}
+}
+
+// Render writes the report in the given format (text, markdown or json)
+func Render(report *Report, format string, writer io.Writer) error {
+	switch format {
+	case "text":
+		return renderText(report, writer)
+	case "markdown":
+		return renderMarkdown(report, writer)
+	case "json":
+		return renderJSON(report, writer)
+	default:
+		return fmt.Errorf("unsupported diff format: %s (supported: text, markdown, json)", format)
+	}
+}
+
+func renderText(report *Report, writer io.Writer) error {
+	var builder strings.Builder
+
+	builder.WriteString("CLEVER CLOUD CATALOG DIFF\n")
+	builder.WriteString("=========================\n\n")
+	builder.WriteString(fmt.Sprintf("Old: %s\n", report.Old.CreatedAt.Format(time.RFC3339)))
+	builder.WriteString(fmt.Sprintf("New: %s\n\n", report.New.CreatedAt.Format(time.RFC3339)))
+
+	if report.Empty() {
+		builder.WriteString("No changes.\n")
+	}
+
+	for _, section := range sections {
+		changes := report.ChangesIn(section)
+		if len(changes) == 0 {
+			continue
+		}
+
+		title := strings.ToUpper(sectionTitles[section])
+		builder.WriteString(title + "\n")
+		builder.WriteString(strings.Repeat("-", len(title)) + "\n")
+		for _, change := range changes {
+			builder.WriteString(fmt.Sprintf("%s %s %s\n", kindMarker(change.Kind), change.Item, describe(change)))
+		}
+		builder.WriteString("\n")
+	}
+
+	_, err := writer.Write([]byte(builder.String()))
+	return err
+}
+
+func renderMarkdown(report *Report, writer io.Writer) error {
+	var builder strings.Builder
+
+	builder.WriteString("# Clever Cloud Catalog Diff\n\n")
+	builder.WriteString(fmt.Sprintf("Comparing catalog of %s with catalog of %s.\n\n",
+		report.Old.CreatedAt.Format(time.RFC3339), report.New.CreatedAt.Format(time.RFC3339)))
+
+	if report.Empty() {
+		builder.WriteString("No changes.\n")
+	}
+
+	for _, section := range sections {
+		changes := report.ChangesIn(section)
+		if len(changes) == 0 {
+			continue
+		}
+
+		builder.WriteString(fmt.Sprintf("## %s\n\n", sectionTitles[section]))
+		builder.WriteString("| Change | Item | Details |\n")
+		builder.WriteString("|--------|------|---------|\n")
+		for _, change := range changes {
+			builder.WriteString(fmt.Sprintf("| %s | `%s` | %s |\n", change.Kind, change.Item, describe(change)))
+		}
+		builder.WriteString("\n")
+	}
+
+	_, err := writer.Write([]byte(builder.String()))
+	return err
+}
+
+// jsonReport is the document emitted by the json diff format
+type jsonReport struct {
+	OldCreatedAt time.Time `json:"old_created_at"`
+	NewCreatedAt time.Time `json:"new_created_at"`
+	Changes      []Change  `json:"changes"`
+}
+
+func renderJSON(report *Report, writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(jsonReport{
+		OldCreatedAt: report.Old.CreatedAt,
+		NewCreatedAt: report.New.CreatedAt,
+		Changes:      report.Changes,
+	})
+}
+
+func kindMarker(kind Kind) string {
+	switch kind {
+	case KindAdded:
+		return "+"
+	case KindRemoved:
+		return "-"
+	default:
+		return "~"
+	}
+}
+
+// describe returns a one-line human-readable description of a change
+func describe(change Change) string {
+	switch change.Kind {
+	case KindAdded:
+		return fmt.Sprintf("added (%v)", change.New)
+	case KindRemoved:
+		return fmt.Sprintf("removed (%v)", change.Old)
+	case KindRenamed:
+		return fmt.Sprintf("renamed from %q to %q", change.Old, change.New)
+	}
+
+	if change.Field == "price" {
+		oldPrice, _ := change.Old.(float64)
+		newPrice, _ := change.New.(float64)
+		details := fmt.Sprintf("price %.2f€ -> %.2f€", oldPrice, newPrice)
+		if oldPrice != 0 {
+			details += fmt.Sprintf(" (%+.1f%%)", (newPrice-oldPrice)/oldPrice*100)
+		}
+		return details
+	}
+
+	return fmt.Sprintf("%s %v -> %v", change.Field, change.Old, change.New)
+}
@@ -0,0 +1,178 @@
+package fakeapi
+
+import (
+	"crypto/sha256"
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// AddonProvidersPath is the path serving the addon providers payload
+	AddonProvidersPath = "/v2/products/addonproviders"
+	// InstancesPath is the path serving the product instances payload
+	InstancesPath = "/v2/products/instances"
+	// ZonesPath is the path serving the zones payload
+	ZonesPath = "/v4/products/zones"
+)
+
+//go:embed fixtures/addonproviders.json
+var defaultProviders []byte
+
+//go:embed fixtures/instances.json
+var defaultInstances []byte
+
+//go:embed fixtures/zones.json
+var defaultZones []byte
+
+// Options configures the payloads served by the fake API and the faults it injects
+type Options struct {
+	// Providers, Instances and Zones are the raw JSON payloads served on the
+	// product endpoints. The bundled fixtures are used when they are empty.
+	Providers []byte
+	Instances []byte
+	Zones     []byte
+
+	// Latency delays every response
+	Latency time.Duration
+
+	// FailStatus, when set, is returned instead of the payload (e.g. 401, 429, 500)
+	FailStatus int
+	// FailCount limits FailStatus to the first N requests; 0 fails every request
+	FailCount int
+	// RetryAfter is sent as a Retry-After header (in seconds) with failed responses
+	RetryAfter time.Duration
+
+	// Malformed serves truncated JSON instead of the payload
+	Malformed bool
+
+	// ETag sends an ETag with payloads and answers matching If-None-Match
+	// requests with 304 Not Modified
+	ETag bool
+}
+
+// Server is an http.Handler standing in for the Clever Cloud product API
+type Server struct {
+	opts Options
+
+	mu       sync.Mutex
+	requests int
+}
+
+// New creates a fake API server with the given options
+func New(opts Options) *Server {
+	if len(opts.Providers) == 0 {
+		opts.Providers = defaultProviders
+	}
+	if len(opts.Instances) == 0 {
+		opts.Instances = defaultInstances
+	}
+	if len(opts.Zones) == 0 {
+		opts.Zones = defaultZones
+	}
+
+	return &Server{opts: opts}
+}
+
+// LoadOptions returns options serving the given fixture files. Empty paths keep
+// the bundled fixtures.
+func LoadOptions(providersPath, instancesPath, zonesPath string) (Options, error) {
+	var opts Options
+
+	if providersPath != "" {
+		data, err := os.ReadFile(providersPath)
+		if err != nil {
+			return opts, fmt.Errorf("failed to read providers fixture: %w", err)
+		}
+		opts.Providers = data
+	}
+
+	if instancesPath != "" {
+		data, err := os.ReadFile(instancesPath)
+		if err != nil {
+			return opts, fmt.Errorf("failed to read instances fixture: %w", err)
+		}
+		opts.Instances = data
+	}
+
+	if zonesPath != "" {
+		data, err := os.ReadFile(zonesPath)
+		if err != nil {
+			return opts, fmt.Errorf("failed to read zones fixture: %w", err)
+		}
+		opts.Zones = data
+	}
+
+	return opts, nil
+}
+
+// Requests returns the number of requests handled so far
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// ServeHTTP serves the product endpoints, applying the configured faults
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests++
+	count := s.requests
+	s.mu.Unlock()
+
+	if s.opts.Latency > 0 {
+		select {
+		case <-time.After(s.opts.Latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var payload []byte
+	switch r.URL.Path {
+	case AddonProvidersPath:
+		payload = s.opts.Providers
+	case InstancesPath:
+		payload = s.opts.Instances
+	case ZonesPath:
+		payload = s.opts.Zones
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	if s.opts.FailStatus != 0 && (s.opts.FailCount == 0 || count <= s.opts.FailCount) {
+		if s.opts.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(s.opts.RetryAfter.Seconds())))
+		}
+		http.Error(w, http.StatusText(s.opts.FailStatus), s.opts.FailStatus)
+		return
+	}
+
+	if s.opts.Malformed {
+		payload = payload[:len(payload)/2]
+	}
+
+	if s.opts.ETag {
+		sum := sha256.Sum256(payload)
+		etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(payload)
+}
@@ -0,0 +1,142 @@
+package fakeapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cc-plans-lister/pkg/clevercloud"
+)
+
+func get(t *testing.T, url string) (*http.Response, []byte) {
+	t.Helper()
+
+	res, err := http.Get(url)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	return res, body
+}
+
+func TestServerFixtures(t *testing.T) {
+	server := httptest.NewServer(New(Options{}))
+	defer server.Close()
+
+	res, body := get(t, server.URL+AddonProvidersPath)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "application/json", res.Header.Get("Content-Type"))
+
+	var providers []clevercloud.AddonProvider
+	require.NoError(t, json.Unmarshal(body, &providers))
+	assert.NotEmpty(t, providers)
+
+	res, body = get(t, server.URL+InstancesPath)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	var instances []clevercloud.ProductInstance
+	require.NoError(t, json.Unmarshal(body, &instances))
+	assert.NotEmpty(t, instances)
+
+	res, body = get(t, server.URL+ZonesPath)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	var zones []clevercloud.Zone
+	require.NoError(t, json.Unmarshal(body, &zones))
+	assert.NotEmpty(t, zones)
+
+	res, _ = get(t, server.URL+"/v2/unknown")
+	assert.Equal(t, http.StatusNotFound, res.StatusCode)
+}
+
+func TestServerCustomFixtures(t *testing.T) {
+	dir := t.TempDir()
+	providersPath := filepath.Join(dir, "providers.json")
+	require.NoError(t, os.WriteFile(providersPath, []byte(`[{"id":"custom","name":"Custom","plans":[]}]`), 0o644))
+
+	opts, err := LoadOptions(providersPath, "", "")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(New(opts))
+	defer server.Close()
+
+	_, body := get(t, server.URL+AddonProvidersPath)
+	assert.JSONEq(t, `[{"id":"custom","name":"Custom","plans":[]}]`, string(body))
+
+	// Instances keep the bundled fixture
+	_, body = get(t, server.URL+InstancesPath)
+	assert.Equal(t, defaultInstances, body)
+
+	_, err = LoadOptions(filepath.Join(dir, "missing.json"), "", "")
+	assert.Error(t, err)
+}
+
+func TestServerFailures(t *testing.T) {
+	server := New(Options{FailStatus: http.StatusTooManyRequests, FailCount: 2, RetryAfter: 3 * time.Second})
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	for i := 0; i < 2; i++ {
+		res, _ := get(t, ts.URL+InstancesPath)
+		assert.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+		assert.Equal(t, "3", res.Header.Get("Retry-After"))
+	}
+
+	res, _ := get(t, ts.URL+InstancesPath)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, 3, server.Requests())
+}
+
+func TestServerMalformed(t *testing.T) {
+	server := httptest.NewServer(New(Options{Malformed: true}))
+	defer server.Close()
+
+	res, body := get(t, server.URL+AddonProvidersPath)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	var providers []clevercloud.AddonProvider
+	assert.Error(t, json.Unmarshal(body, &providers))
+}
+
+func TestServerETag(t *testing.T) {
+	server := httptest.NewServer(New(Options{ETag: true}))
+	defer server.Close()
+
+	res, _ := get(t, server.URL+InstancesPath)
+	etag := res.Header.Get("ETag")
+	require.NotEmpty(t, etag)
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+InstancesPath, nil)
+	require.NoError(t, err)
+	req.Header.Set("If-None-Match", etag)
+
+	res, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusNotModified, res.StatusCode)
+}
+
+func TestServerLatency(t *testing.T) {
+	server := httptest.NewServer(New(Options{Latency: time.Second}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+InstancesPath, nil)
+	require.NoError(t, err)
+
+	_, err = http.DefaultClient.Do(req)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,188 @@
+package filter
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"cc-plans-lister/pkg/clevercloud"
+)
+
+// Options selects the part of the catalog to report on. Provider options
+// apply to addon providers and instance, tag, deployment and flavor options
+// to application instance types, so each side is narrowed independently.
+// The zero value keeps every provider and the enabled instance types that
+// are not coming soon.
+type Options struct {
+	Zone          string   // only addon plans available in this zone, see ByZone
+	Providers     []string // addon provider IDs
+	InstanceTypes []string // instance types, e.g. "node" or "go"
+	Tags          []string // instance types carrying any of these tags
+	Deployments   []string // instance types supporting any of these deployment methods
+
+	MinMemory        clevercloud.ByteSize // flavors with at least this much memory
+	MaxPrice         float64              // flavors costing at most this hourly price, 0 for any price
+	AvailableOnly    bool                 // flavors that can currently be selected
+	MLOnly           bool                 // machine learning flavors
+	MicroserviceOnly bool                 // flavors usable for microservices
+
+	IncludeDisabled   bool // keep disabled instance types
+	IncludeComingSoon bool // keep instance types announced as coming soon
+}
+
+// filtersFlavors reports whether the options select flavors, in which case
+// instance types left without any flavor are dropped
+func (o Options) filtersFlavors() bool {
+	return o.MinMemory > 0 || o.MaxPrice > 0 || o.AvailableOnly || o.MLOnly || o.MicroserviceOnly
+}
+
+// Apply narrows the catalog down to the items selected by the options.
+// Names are matched case-insensitively, and the source slices are left untouched.
+func Apply(providers []clevercloud.AddonProvider, instances []clevercloud.ProductInstance, opts Options) ([]clevercloud.AddonProvider, []clevercloud.ProductInstance) {
+	if opts.Zone != "" {
+		providers = ByZone(providers, opts.Zone)
+	}
+
+	var filteredProviders []clevercloud.AddonProvider
+	for _, provider := range providers {
+		if matchAny(opts.Providers, provider.ID) {
+			filteredProviders = append(filteredProviders, provider)
+		}
+	}
+
+	var filteredInstances []clevercloud.ProductInstance
+	for _, instance := range instances {
+		if !opts.keepInstance(instance) {
+			continue
+		}
+
+		if opts.filtersFlavors() {
+			var flavors []clevercloud.Flavor
+			for _, flavor := range instance.Flavors {
+				if opts.keepFlavor(flavor) {
+					flavors = append(flavors, flavor)
+				}
+			}
+			if len(flavors) == 0 {
+				continue
+			}
+			instance.Flavors = flavors
+		}
+
+		filteredInstances = append(filteredInstances, instance)
+	}
+
+	return filteredProviders, filteredInstances
+}
+
+// keepInstance reports whether an instance type is selected, leaving its flavors aside
+func (o Options) keepInstance(instance clevercloud.ProductInstance) bool {
+	switch {
+	case instance.ComingSoon && !o.IncludeComingSoon:
+		return false
+	case !instance.Enabled && !instance.ComingSoon && !o.IncludeDisabled:
+		return false
+	}
+
+	return matchAny(o.InstanceTypes, instance.Type) &&
+		matchAnyOf(o.Tags, instance.Tags) &&
+		matchAnyOf(o.Deployments, instance.Deployments)
+}
+
+// keepFlavor reports whether a flavor is selected
+func (o Options) keepFlavor(flavor clevercloud.Flavor) bool {
+	switch {
+	case flavor.MemorySize() < o.MinMemory:
+		return false
+	case o.MaxPrice > 0 && flavor.Price > o.MaxPrice:
+		return false
+	case o.AvailableOnly && !flavor.Available:
+		return false
+	case o.MLOnly && !flavor.MachineLearning:
+		return false
+	case o.MicroserviceOnly && !flavor.Microservice:
+		return false
+	}
+	return true
+}
+
+// matchAny reports whether value is one of the wanted names; an empty list matches anything
+func matchAny(wanted []string, value string) bool {
+	if len(wanted) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(wanted, func(name string) bool { return strings.EqualFold(name, value) })
+}
+
+// matchAnyOf reports whether one of the values is a wanted name; an empty list matches anything
+func matchAnyOf(wanted []string, values []string) bool {
+	if len(wanted) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(values, func(value string) bool { return matchAny(wanted, value) })
+}
+
+// ByZone keeps the addon plans available in the given zone. Plans without zone
+// information fall back to the regions of their provider, and plans without
+// any zone information are kept since their availability is unknown.
+// Providers are dropped when none of their plans remain. Instance types are
+// not filtered: the API does not publish in which zones they are available.
+func ByZone(providers []clevercloud.AddonProvider, zone string) []clevercloud.AddonProvider {
+	var filteredProviders []clevercloud.AddonProvider
+	for _, provider := range providers {
+		if len(provider.Plans) == 0 {
+			if inZone(provider.Regions, zone) {
+				filteredProviders = append(filteredProviders, provider)
+			}
+			continue
+		}
+
+		var plans []clevercloud.AddonPlan
+		for _, plan := range provider.Plans {
+			if inZone(provider.PlanZones(plan), zone) {
+				plans = append(plans, plan)
+			}
+		}
+
+		if len(plans) > 0 {
+			provider.Plans = plans
+			filteredProviders = append(filteredProviders, provider)
+		}
+	}
+
+	return filteredProviders
+}
+
+// CatalogZones returns the sorted zones referenced by addon plans and provider regions
+func CatalogZones(providers []clevercloud.AddonProvider) []string {
+	var zones []string
+	for _, provider := range providers {
+		for _, plan := range provider.Plans {
+			zones = append(zones, plan.Zones...)
+		}
+		zones = append(zones, provider.Regions...)
+	}
+
+	slices.Sort(zones)
+	return slices.Compact(zones)
+}
+
+// CheckZone reports an error when zone is not one of the known zones, so that
+// a typo does not silently produce an empty report
+func CheckZone(zone string, known []string) error {
+	if slices.Contains(known, zone) {
+		return nil
+	}
+	if len(known) == 0 {
+		return fmt.Errorf("unknown zone %q: the catalog does not reference any zone", zone)
+	}
+
+	known = slices.Clone(known)
+	slices.Sort(known)
+	return fmt.Errorf("unknown zone %q (known zones: %s)", zone, strings.Join(slices.Compact(known), ", "))
+}
+
+// inZone reports whether a zone list includes the zone; an empty list means unknown
+func inZone(zones []string, zone string) bool {
+	return len(zones) == 0 || slices.Contains(zones, zone)
+}
@@ -0,0 +1,188 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cc-plans-lister/pkg/clevercloud"
+	"cc-plans-lister/test/fixtures"
+)
+
+func TestByZone(t *testing.T) {
+	providers := ByZone(fixtures.TestAddonProviders(), "rbx")
+
+	// Redis keeps its small plan only, the postgresql dev plan has no zone
+	// information and inherits the (empty) provider regions
+	assert.Len(t, providers, 2)
+	assert.Equal(t, "redis", providers[0].ID)
+	assert.Len(t, providers[0].Plans, 1)
+	assert.Equal(t, "small", providers[0].Plans[0].Slug)
+	assert.Equal(t, "postgresql", providers[1].ID)
+	assert.Len(t, providers[1].Plans, 2)
+
+	// The source catalog is left untouched
+	assert.Len(t, fixtures.TestAddonProviders()[0].Plans, 2)
+}
+
+func TestByZoneDropsEmptyProviders(t *testing.T) {
+	providers := ByZone(fixtures.TestAddonProviders(), "sgp")
+
+	// Only postgresql remains, through its dev plan without zone information
+	assert.Len(t, providers, 1)
+	assert.Equal(t, "postgresql", providers[0].ID)
+	assert.Len(t, providers[0].Plans, 1)
+	assert.Equal(t, "dev", providers[0].Plans[0].Slug)
+}
+
+func TestCatalogZones(t *testing.T) {
+	providers := fixtures.TestAddonProviders()
+	providers[1].Regions = []string{"sgp", "par"}
+
+	zones := CatalogZones(providers)
+	assert.Equal(t, []string{"mtl", "par", "rbx", "sgp"}, zones)
+}
+
+func TestApplyZoneKeepsInstances(t *testing.T) {
+	// Instance types are not filtered by zone, their availability is not published
+	providers, instances := Apply(fixtures.TestAddonProviders(), fixtures.TestProductInstances(), Options{Zone: "mtl"})
+	require.Len(t, providers, 1)
+	assert.Equal(t, "postgresql", providers[0].ID)
+	assert.Len(t, instances, 2)
+}
+
+func TestCheckZone(t *testing.T) {
+	require.NoError(t, CheckZone("par", []string{"rbx", "par"}))
+
+	err := CheckZone("pra", []string{"rbx", "par", "rbx"})
+	require.Error(t, err)
+	assert.Equal(t, `unknown zone "pra" (known zones: par, rbx)`, err.Error())
+
+	err = CheckZone("par", nil)
+	assert.ErrorContains(t, err, "does not reference any zone")
+}
+
+func TestApplyDefaults(t *testing.T) {
+	instances := fixtures.TestProductInstances()
+	instances[0].Enabled = false
+	instances = append(instances, fixtures.TestProductInstances()[1])
+	instances[2].Type = "go"
+	instances[2].ComingSoon = true
+
+	providers, filtered := Apply(fixtures.TestAddonProviders(), instances, Options{})
+
+	// Providers are kept, disabled and coming soon instance types are dropped
+	assert.Len(t, providers, 2)
+	assert.Len(t, filtered, 1)
+	assert.Equal(t, "python", filtered[0].Type)
+
+	_, filtered = Apply(nil, instances, Options{IncludeDisabled: true})
+	assert.Len(t, filtered, 2)
+	assert.Equal(t, "node", filtered[0].Type)
+
+	_, filtered = Apply(nil, instances, Options{IncludeComingSoon: true})
+	assert.Len(t, filtered, 2)
+	assert.Equal(t, "go", filtered[1].Type)
+}
+
+func TestApplyAvailability(t *testing.T) {
+	tests := []struct {
+		name              string
+		enabled           bool
+		comingSoon        bool
+		includeDisabled   bool
+		includeComingSoon bool
+		kept              bool
+	}{
+		{name: "enabled", enabled: true, kept: true},
+		{name: "disabled", kept: false},
+		{name: "disabled included", includeDisabled: true, kept: true},
+		{name: "enabled coming soon", enabled: true, comingSoon: true, kept: false},
+		{name: "enabled coming soon with disabled", enabled: true, comingSoon: true, includeDisabled: true, kept: false},
+		{name: "enabled coming soon included", enabled: true, comingSoon: true, includeComingSoon: true, kept: true},
+		{name: "disabled coming soon", comingSoon: true, kept: false},
+		{name: "disabled coming soon with disabled", comingSoon: true, includeDisabled: true, kept: false},
+		{name: "disabled coming soon included", comingSoon: true, includeComingSoon: true, kept: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			instances := fixtures.TestProductInstances()[:1]
+			instances[0].Enabled = tt.enabled
+			instances[0].ComingSoon = tt.comingSoon
+
+			_, filtered := Apply(nil, instances, Options{
+				IncludeDisabled:   tt.includeDisabled,
+				IncludeComingSoon: tt.includeComingSoon,
+			})
+			assert.Equal(t, tt.kept, len(filtered) == 1)
+		})
+	}
+}
+
+func TestApplyNames(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      Options
+		providers []string
+		instances []string
+	}{
+		{"provider", Options{Providers: []string{"REDIS"}}, []string{"redis"}, []string{"node", "python"}},
+		{"instance type", Options{InstanceTypes: []string{"node", "go"}}, []string{"redis", "postgresql"}, []string{"node"}},
+		{"tag", Options{Tags: []string{"python", "ruby"}}, []string{"redis", "postgresql"}, []string{"python"}},
+		{"deployment", Options{Deployments: []string{"docker"}}, []string{"redis", "postgresql"}, []string{"node"}},
+		{"no match", Options{Providers: []string{"mongodb"}, Tags: []string{"php"}}, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			providers, instances := Apply(fixtures.TestAddonProviders(), fixtures.TestProductInstances(), tt.opts)
+
+			var providerIDs, instanceTypes []string
+			for _, provider := range providers {
+				providerIDs = append(providerIDs, provider.ID)
+			}
+			for _, instance := range instances {
+				instanceTypes = append(instanceTypes, instance.Type)
+			}
+			assert.Equal(t, tt.providers, providerIDs)
+			assert.Equal(t, tt.instances, instanceTypes)
+		})
+	}
+}
+
+func TestApplyFlavors(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     Options
+		expected []string // type/flavor
+	}{
+		{"min memory", Options{MinMemory: 512 * clevercloud.MiB}, []string{"node/small", "python/small"}},
+		{"max price", Options{MaxPrice: 0.03}, []string{"node/nano"}},
+		{"microservice", Options{MicroserviceOnly: true}, []string{"node/nano"}},
+		{"machine learning", Options{MLOnly: true}, []string{"python/small"}},
+		{"combined", Options{MinMemory: clevercloud.GiB, AvailableOnly: true}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, instances := Apply(nil, fixtures.TestProductInstances(), tt.opts)
+
+			// Instance types without any selected flavor are dropped
+			var flavors []string
+			for _, instance := range instances {
+				require.NotEmpty(t, instance.Flavors)
+				for _, flavor := range instance.Flavors {
+					flavors = append(flavors, instance.Type+"/"+flavor.Name)
+				}
+			}
+			assert.Equal(t, tt.expected, flavors)
+		})
+	}
+
+	// The source catalog is left untouched
+	instances := fixtures.TestProductInstances()
+	Apply(nil, instances, Options{MLOnly: true})
+	assert.Len(t, instances[0].Flavors, 2)
+}
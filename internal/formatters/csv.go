@@ -2,112 +2,214 @@ package formatters
 
 import (
 	"encoding/csv"
+	"fmt"
 	"io"
-	"sort"
+	"os"
+	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"cc-plans-lister/pkg/clevercloud"
 )
 
+// CSV layouts
+const (
+	// CSVLayoutCombined writes every section in one file, each introduced by
+	// a "# SECTION" comment row and separated by blank rows
+	CSVLayoutCombined = "combined"
+	// CSVLayoutSplit writes one file per section (addons.csv, instances.csv
+	// and zones.csv) with a single header row each
+	CSVLayoutSplit = "split"
+	// CSVLayoutFlat writes a single table with one schema for addon plans and
+	// flavors, told apart by its kind column
+	CSVLayoutFlat = "flat"
+)
+
+// CSVLayouts lists the supported CSV layouts, the default first
+var CSVLayouts = []string{CSVLayoutCombined, CSVLayoutSplit, CSVLayoutFlat}
+
 // CSVFormatter generates CSV output
-type CSVFormatter struct{}
+type CSVFormatter struct {
+	// Layout is one of CSVLayouts; empty means CSVLayoutCombined
+	Layout string
+	// Delimiter separates fields; zero means a comma
+	Delimiter rune
+	// Decimal is the decimal separator of prices, "." (the default) or ","
+	Decimal string
+}
 
-// Format generates CSV output for addon providers and product instances
-func (f *CSVFormatter) Format(providers []clevercloud.AddonProvider, instances []clevercloud.ProductInstance, writer io.Writer) error {
-	csvWriter := csv.NewWriter(writer)
-	defer csvWriter.Flush()
+func init() {
+	Register(Format{
+		Name:        "csv",
+		Extension:   ".csv",
+		MIMEType:    "text/csv",
+		Description: "CSV export for spreadsheets",
+		New: func(opts Options) Formatter {
+			return &CSVFormatter{Layout: opts.CSVLayout, Delimiter: opts.CSVDelimiter, Decimal: opts.CSVDecimal}
+		},
+	})
+}
 
-	// Write header comment (as a single-column row)
-	err := csvWriter.Write([]string{"# Complete Clever Cloud Services Overview - CSV Export"})
-	if err != nil {
-		return err
+// CheckCSVOptions reports invalid CSV settings, so that they are rejected
+// before the catalog is fetched
+func CheckCSVOptions(opts Options) error {
+	return (&CSVFormatter{Layout: opts.CSVLayout, Delimiter: opts.CSVDelimiter, Decimal: opts.CSVDecimal}).check()
+}
+
+// check validates the formatter settings
+func (f *CSVFormatter) check() error {
+	if f.Layout != "" && !slices.Contains(CSVLayouts, f.Layout) {
+		return fmt.Errorf("unsupported CSV layout: %s (supported: %s)", f.Layout, strings.Join(CSVLayouts, ", "))
+	}
+	if f.Decimal != "" && f.Decimal != "." && f.Decimal != "," {
+		return fmt.Errorf("unsupported CSV decimal separator %q (supported: \".\", \",\")", f.Decimal)
 	}
 
-	err = csvWriter.Write([]string{"# Automatically generated via Clever Cloud API"})
-	if err != nil {
-		return err
+	delimiter := f.delimiter()
+	if delimiter == '"' || delimiter == '\r' || delimiter == '\n' || delimiter == utf8.RuneError {
+		return fmt.Errorf("invalid CSV delimiter %q", delimiter)
+	}
+	if string(delimiter) == f.decimal() {
+		return fmt.Errorf("the CSV delimiter and decimal separator must differ")
 	}
 
-	err = csvWriter.Write([]string{}) // empty row
-	if err != nil {
+	return nil
+}
+
+// WritesDirectory reports whether the split layout is selected
+func (f *CSVFormatter) WritesDirectory() bool {
+	return f.Layout == CSVLayoutSplit
+}
+
+// Format generates CSV output for addon providers and product instances in
+// the combined or flat layout
+func (f *CSVFormatter) Format(providers []clevercloud.AddonProvider, instances []clevercloud.ProductInstance, writer io.Writer) error {
+	if err := f.check(); err != nil {
 		return err
 	}
 
-	// Addon Providers section
-	err = csvWriter.Write([]string{"# ADDON PROVIDERS"})
-	if err != nil {
-		return err
+	switch f.Layout {
+	case CSVLayoutSplit:
+		return fmt.Errorf("the %s CSV layout writes several files and needs an output directory", CSVLayoutSplit)
+	case CSVLayoutFlat:
+		return f.write(writer, f.flatRecords(providers, instances))
 	}
 
-	// Addon providers header
-	err = csvWriter.Write([]string{
-		"Type", "Provider_ID", "Provider_Name", "Plan_ID", "Plan_Name", "Plan_Slug",
-	})
+	records := [][]string{
+		{"# Complete Clever Cloud Services Overview - CSV Export"},
+		{"# Automatically generated via Clever Cloud API"},
+		{}, // empty row
+		{"# ADDON PROVIDERS"},
+	}
+	records = append(records, f.addonRecords(providers)...)
+	records = append(records, []string{}, []string{"# APPLICATION INSTANCES"})
+	records = append(records, f.instanceRecords(instances)...)
+
+	matrix := buildZoneMatrix(providers)
+	if len(matrix.Zones) > 0 {
+		records = append(records, []string{}, []string{"# ZONE AVAILABILITY"})
+		records = append(records, zoneRecords(matrix)...)
+	}
+
+	return f.write(writer, records)
+}
+
+// FormatDirectory writes the split layout: addons.csv, instances.csv and,
+// when zones are known, zones.csv in dir. It returns the written files.
+func (f *CSVFormatter) FormatDirectory(providers []clevercloud.AddonProvider, instances []clevercloud.ProductInstance, dir string) ([]string, error) {
+	if err := f.check(); err != nil {
+		return nil, err
+	}
+
+	names := []string{"addons.csv", "instances.csv"}
+	tables := [][][]string{f.addonRecords(providers), f.instanceRecords(instances)}
+	if matrix := buildZoneMatrix(providers); len(matrix.Zones) > 0 {
+		names = append(names, "zones.csv")
+		tables = append(tables, zoneRecords(matrix))
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	paths := make([]string, len(names))
+	for i, name := range names {
+		paths[i] = filepath.Join(dir, name)
+		if err := f.writeFile(paths[i], tables[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	return paths, nil
+}
+
+// writeFile writes records to a new file
+func (f *CSVFormatter) writeFile(path string, records [][]string) error {
+	out, err := os.Create(path)
 	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer out.Close()
+
+	if err := f.write(out, records); err != nil {
 		return err
 	}
+	return out.Close()
+}
+
+// write writes records with the configured delimiter
+func (f *CSVFormatter) write(writer io.Writer, records [][]string) error {
+	csvWriter := csv.NewWriter(writer)
+	csvWriter.Comma = f.delimiter()
+	return csvWriter.WriteAll(records)
+}
+
+// addonRecords returns the addon plans table, header included
+func (f *CSVFormatter) addonRecords(providers []clevercloud.AddonProvider) [][]string {
+	records := [][]string{{
+		"Type", "Provider_ID", "Provider_Name", "Plan_ID", "Plan_Name", "Plan_Slug",
+		"Plan_Price", "Plan_Features", "Plan_Zones",
+	}}
 
-	// Write addon data
 	for _, provider := range providers {
 		if len(provider.Plans) == 0 {
-			err = csvWriter.Write([]string{
-				"addon", provider.ID, provider.Name, "", "No plans available", "",
+			records = append(records, []string{
+				"addon", provider.ID, provider.Name, "", "No plans available", "", "", "", "",
 			})
-			if err != nil {
-				return err
-			}
 			continue
 		}
 
-		// Sort plans by slug for consistent output
-		plans := make([]clevercloud.AddonPlan, len(provider.Plans))
-		copy(plans, provider.Plans)
-		sort.Slice(plans, func(i, j int) bool {
-			return plans[i].Slug < plans[j].Slug
-		})
-
-		for _, plan := range plans {
-			err = csvWriter.Write([]string{
+		for _, plan := range sortedPlans(provider) {
+			records = append(records, []string{
 				"addon",
 				provider.ID,
 				provider.Name,
 				plan.ID,
 				plan.Name,
 				plan.Slug,
+				f.price(plan.Price),
+				formatPlanFeatures(plan, "|"),
+				strings.Join(provider.PlanZones(plan), "|"),
 			})
-			if err != nil {
-				return err
-			}
 		}
 	}
 
-	// Empty row separator
-	err = csvWriter.Write([]string{})
-	if err != nil {
-		return err
-	}
-
-	// Application Instances section
-	err = csvWriter.Write([]string{"# APPLICATION INSTANCES"})
-	if err != nil {
-		return err
-	}
+	return records
+}
 
-	// Application instances header
-	err = csvWriter.Write([]string{
+// instanceRecords returns the application flavors table, header included
+func (f *CSVFormatter) instanceRecords(instances []clevercloud.ProductInstance) [][]string {
+	records := [][]string{{
 		"Type", "Instance_Type", "Instance_Name", "Version", "Description", "Enabled", "Max_Instances",
-		"Tags", "Deployments", "Flavor_Name", "Flavor_Slug", "Memory_Formatted", "Memory_Value", "Memory_Unit",
+		"Tags", "Deployments", "Flavor_Name", "Flavor_Slug", "Memory_Formatted", "Memory_Bytes", "Disk_Formatted", "Disk_Bytes",
 		"CPUs", "GPUs", "Price", "Available", "Microservice", "MachineLearning", "IsDefault",
-	})
-	if err != nil {
-		return err
-	}
+	}}
 
-	// Write application data
 	for _, instance := range instances {
 		if len(instance.Flavors) == 0 {
-			err = csvWriter.Write([]string{
+			records = append(records, []string{
 				"application",
 				instance.Type,
 				instance.Name,
@@ -117,35 +219,15 @@ func (f *CSVFormatter) Format(providers []clevercloud.AddonProvider, instances [
 				strconv.Itoa(instance.MaxInstances),
 				strings.Join(instance.Tags, "|"),
 				strings.Join(instance.Deployments, "|"),
-				"", "", "", "", "", "", "", "", "", "", "", "",
+				"", "", "", "", "", "", "", "", "", "", "", "", "",
 			})
-			if err != nil {
-				return err
-			}
 			continue
 		}
 
-		// Sort flavors by name for consistent output
-		flavors := make([]clevercloud.Flavor, len(instance.Flavors))
-		copy(flavors, instance.Flavors)
-		sort.Slice(flavors, func(i, j int) bool {
-			return flavors[i].Name < flavors[j].Name
-		})
-
-		for _, flavor := range flavors {
+		for _, flavor := range sortedFlavors(instance) {
 			isDefault := flavor.Name == instance.DefaultFlavor.Name
 
-			// Use flavor slug if available, otherwise use PriceID or name as fallback
-			flavorSlug := flavor.Slug
-			if flavorSlug == "" {
-				if flavor.PriceID != "" {
-					flavorSlug = flavor.PriceID
-				} else {
-					flavorSlug = flavor.Name
-				}
-			}
-
-			err = csvWriter.Write([]string{
+			records = append(records, []string{
 				"application",
 				instance.Type,
 				instance.Name,
@@ -156,23 +238,131 @@ func (f *CSVFormatter) Format(providers []clevercloud.AddonProvider, instances [
 				strings.Join(instance.Tags, "|"),
 				strings.Join(instance.Deployments, "|"),
 				flavor.Name,
-				flavorSlug,
-				flavor.Memory.Formatted,
-				strconv.Itoa(flavor.Memory.Value),
-				flavor.Memory.Unit,
+				flavor.EffectiveSlug(),
+				flavor.MemorySize().String(),
+				strconv.FormatInt(int64(flavor.MemorySize()), 10),
+				flavor.Disk.String(),
+				diskBytes(flavor),
 				strconv.Itoa(flavor.Cpus),
 				strconv.Itoa(flavor.Gpus),
-				strconv.FormatFloat(flavor.Price, 'f', 2, 64),
+				f.price(flavor.Price),
 				strconv.FormatBool(flavor.Available),
 				strconv.FormatBool(flavor.Microservice),
 				strconv.FormatBool(flavor.MachineLearning),
 				strconv.FormatBool(isDefault),
 			})
-			if err != nil {
-				return err
-			}
 		}
 	}
 
-	return nil
+	return records
+}
+
+// flatRecords returns addon plans and flavors in a single table. Columns
+// that do not apply to a kind are left empty; prices are per month for
+// addon plans and per hour for flavors, as told by price_period.
+func (f *CSVFormatter) flatRecords(providers []clevercloud.AddonProvider, instances []clevercloud.ProductInstance) [][]string {
+	records := [][]string{{
+		"kind", "id", "name", "version", "enabled", "item_id", "item_name", "item_slug",
+		"price", "price_period", "memory_bytes", "disk_bytes", "cpus", "gpus",
+		"available", "microservice", "machine_learning", "is_default", "features", "zones", "tags", "deployments",
+	}}
+
+	for _, provider := range providers {
+		if len(provider.Plans) == 0 {
+			records = append(records, []string{
+				"addon", provider.ID, provider.Name, "", "", "", "", "",
+				"", "", "", "", "", "",
+				"", "", "", "", "", strings.Join(provider.Regions, "|"), "", "",
+			})
+			continue
+		}
+
+		for _, plan := range sortedPlans(provider) {
+			records = append(records, []string{
+				"addon", provider.ID, provider.Name, "", "", plan.ID, plan.Name, plan.Slug,
+				f.price(plan.Price), "month", "", "", "", "",
+				"", "", "", "", formatPlanFeatures(plan, "|"), strings.Join(provider.PlanZones(plan), "|"), "", "",
+			})
+		}
+	}
+
+	for _, instance := range instances {
+		enabled := strconv.FormatBool(instance.Enabled)
+		tags := strings.Join(instance.Tags, "|")
+		deployments := strings.Join(instance.Deployments, "|")
+
+		if len(instance.Flavors) == 0 {
+			records = append(records, []string{
+				"application", instance.Type, instance.Name, instance.Version, enabled, "", "", "",
+				"", "", "", "", "", "",
+				"", "", "", "", "", "", tags, deployments,
+			})
+			continue
+		}
+
+		for _, flavor := range sortedFlavors(instance) {
+			records = append(records, []string{
+				"application", instance.Type, instance.Name, instance.Version, enabled,
+				flavor.PriceID, flavor.Name, flavor.EffectiveSlug(),
+				f.price(flavor.Price), "hour",
+				strconv.FormatInt(int64(flavor.MemorySize()), 10), diskBytes(flavor),
+				strconv.Itoa(flavor.Cpus), strconv.Itoa(flavor.Gpus),
+				strconv.FormatBool(flavor.Available), strconv.FormatBool(flavor.Microservice),
+				strconv.FormatBool(flavor.MachineLearning), strconv.FormatBool(flavor.Name == instance.DefaultFlavor.Name),
+				"", "", tags, deployments,
+			})
+		}
+	}
+
+	return records
+}
+
+// zoneRecords returns the zone availability matrix, header included
+func zoneRecords(matrix zoneMatrix) [][]string {
+	records := [][]string{append([]string{"Addon_Plan"}, matrix.Zones...)}
+
+	for _, row := range matrix.Rows {
+		record := []string{row.Item}
+		for _, zone := range matrix.Zones {
+			record = append(record, strconv.FormatBool(row.Zones[zone]))
+		}
+		records = append(records, record)
+	}
+
+	// Unknown availability is left empty rather than reported as false
+	for _, row := range matrix.Unknown {
+		records = append(records, append([]string{row.Item}, make([]string, len(matrix.Zones))...))
+	}
+
+	return records
+}
+
+// diskBytes returns the disk size of a flavor in bytes, so that spreadsheets
+// can sort and compute on it; an unknown size is left empty rather than set to 0
+func diskBytes(flavor clevercloud.Flavor) string {
+	if !flavor.Disk.Known {
+		return ""
+	}
+	return strconv.FormatInt(int64(flavor.Disk.Size), 10)
+}
+
+// price formats a price with two decimals and the configured decimal separator
+func (f *CSVFormatter) price(price float64) string {
+	return strings.Replace(strconv.FormatFloat(price, 'f', 2, 64), ".", f.decimal(), 1)
+}
+
+// delimiter returns the field delimiter, a comma by default
+func (f *CSVFormatter) delimiter() rune {
+	if f.Delimiter == 0 {
+		return ','
+	}
+	return f.Delimiter
+}
+
+// decimal returns the decimal separator, a dot by default
+func (f *CSVFormatter) decimal() string {
+	if f.Decimal == "" {
+		return "."
+	}
+	return f.Decimal
 }
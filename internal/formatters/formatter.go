@@ -1,7 +1,10 @@
 package formatters
 
 import (
+	"fmt"
 	"io"
+	"sort"
+	"strings"
 
 	"cc-plans-lister/pkg/clevercloud"
 )
@@ -11,18 +14,159 @@ type Formatter interface {
 	Format(providers []clevercloud.AddonProvider, instances []clevercloud.ProductInstance, writer io.Writer) error
 }
 
-// GetFormatter returns the appropriate formatter based on the format string
-func GetFormatter(format string) Formatter {
-	switch format {
-	case "markdown":
-		return &MarkdownFormatter{}
-	case "txt":
-		return &TextFormatter{}
-	case "csv":
-		return &CSVFormatter{}
-	case "pdf":
-		return &PDFFormatter{}
-	default:
-		return &MarkdownFormatter{} // default to markdown
+// DirectoryFormatter is implemented by formatters that can write their output
+// as several files. When WritesDirectory reports true, FormatDirectory is used
+// instead of Format and returns the paths of the files written in dir.
+type DirectoryFormatter interface {
+	Formatter
+	WritesDirectory() bool
+	FormatDirectory(providers []clevercloud.AddonProvider, instances []clevercloud.ProductInstance, dir string) ([]string, error)
+}
+
+// Options holds the settings of the formatters that accept some. The zero
+// value gives the default output of every format.
+type Options struct {
+	// PDFFont is the path of a TrueType font used in PDF reports instead of
+	// the bundled DejaVu Sans Condensed
+	PDFFont string
+	// PDFLandscape lays out the application flavors table of PDF reports in landscape
+	PDFLandscape bool
+	// Source describes where the catalog comes from (API endpoint or
+	// snapshot file); it is shown on the cover page of PDF reports
+	Source string
+	// Template is the template file rendered by the template format
+	Template string
+	// CSVLayout is the layout of CSV exports, one of CSVLayouts
+	CSVLayout string
+	// CSVDelimiter separates the fields of CSV exports (default: comma)
+	CSVDelimiter rune
+	// CSVDecimal is the decimal separator of prices in CSV exports (default: ".")
+	CSVDecimal string
+}
+
+// formatPlanFeatures renders the features of an addon plan as "Name: Value" pairs
+func formatPlanFeatures(plan clevercloud.AddonPlan, separator string) string {
+	features := make([]string, 0, len(plan.Features))
+	for _, feature := range plan.Features {
+		features = append(features, fmt.Sprintf("%s: %s", feature.Name, feature.Value))
+	}
+	return strings.Join(features, separator)
+}
+
+// formatDisk renders the disk size of a flavor, or "-" when the API does not specify one
+func formatDisk(flavor clevercloud.Flavor) string {
+	if !flavor.Disk.Known {
+		return "-"
+	}
+	return flavor.Disk.String()
+}
+
+// formatFlavorResources summarizes the memory, CPUs and disk of a flavor,
+// e.g. "512 MiB, 1 CPU, 10 GiB disk"
+func formatFlavorResources(flavor clevercloud.Flavor) string {
+	resources := fmt.Sprintf("%s, %d CPU", flavor.MemorySize(), flavor.Cpus)
+	if flavor.Disk.Known {
+		resources += fmt.Sprintf(", %s disk", flavor.Disk)
+	}
+	return resources
+}
+
+// providerDetail is a labelled piece of addon provider metadata
+type providerDetail struct {
+	Label string
+	Value string
+}
+
+// providerDetails returns the metadata of an addon provider worth displaying,
+// skipping text fields the API left empty. The upgrade capability is a plain
+// boolean, where missing reads as "No", so it is always listed.
+func providerDetails(provider clevercloud.AddonProvider) []providerDetail {
+	var details []providerDetail
+
+	add := func(label, value string) {
+		if value != "" {
+			details = append(details, providerDetail{Label: label, Value: value})
+		}
+	}
+
+	add("Status", strings.ToLower(provider.Status))
+	add("Website", provider.Website)
+	add("Support", provider.SupportEmail)
+	add("Regions", strings.Join(provider.Regions, ", "))
+
+	upgrade := "No"
+	if provider.CanUpgrade {
+		upgrade = "Yes"
 	}
+	add("Upgrade/downgrade", upgrade)
+	add("Logo", provider.LogoURL)
+
+	return details
+}
+
+// zoneMatrixRow tells in which zones an addon plan is available
+type zoneMatrixRow struct {
+	Item  string // provider/plan slug
+	Zones map[string]bool
+}
+
+// zoneMatrix cross-references addon plans with the zones they are available
+// in. Instance types are left out: the API does not publish their zones.
+type zoneMatrix struct {
+	Zones   []string
+	Rows    []zoneMatrixRow
+	Unknown []zoneMatrixRow // plans whose zones the API does not state, without Zones
+}
+
+// UnknownNote tells which plans the matrix cannot place in zones, or returns
+// an empty string when the availability of every plan is known
+func (m zoneMatrix) UnknownNote() string {
+	if len(m.Unknown) == 0 {
+		return ""
+	}
+
+	items := make([]string, len(m.Unknown))
+	for i, row := range m.Unknown {
+		items[i] = row.Item
+	}
+	return "Availability per zone is not published for: " + strings.Join(items, ", ")
+}
+
+// buildZoneMatrix collects the zones of addon plans, falling back to their
+// provider regions. Plans without any zone information are listed as unknown
+// rather than as unavailable everywhere.
+func buildZoneMatrix(providers []clevercloud.AddonProvider) zoneMatrix {
+	var matrix zoneMatrix
+	seen := make(map[string]bool)
+
+	addRow := func(item string, zones []string) {
+		if len(zones) == 0 {
+			matrix.Unknown = append(matrix.Unknown, zoneMatrixRow{Item: item})
+			return
+		}
+		row := zoneMatrixRow{Item: item, Zones: make(map[string]bool, len(zones))}
+		for _, zone := range zones {
+			row.Zones[zone] = true
+			if !seen[zone] {
+				seen[zone] = true
+				matrix.Zones = append(matrix.Zones, zone)
+			}
+		}
+		matrix.Rows = append(matrix.Rows, row)
+	}
+
+	for _, provider := range providers {
+		plans := make([]clevercloud.AddonPlan, len(provider.Plans))
+		copy(plans, provider.Plans)
+		sort.Slice(plans, func(i, j int) bool {
+			return plans[i].Slug < plans[j].Slug
+		})
+
+		for _, plan := range plans {
+			addRow(provider.ID+"/"+plan.Slug, provider.PlanZones(plan))
+		}
+	}
+
+	sort.Strings(matrix.Zones)
+	return matrix
 }
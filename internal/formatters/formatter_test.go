@@ -2,11 +2,20 @@ package formatters
 
 import (
 	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
 
+	"github.com/jung-kurt/gofpdf"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+	"gopkg.in/yaml.v3"
 
+	"cc-plans-lister/pkg/clevercloud"
 	"cc-plans-lister/test/fixtures"
 )
 
@@ -16,18 +25,76 @@ func TestGetFormatter(t *testing.T) {
 		expected interface{}
 	}{
 		{"markdown", &MarkdownFormatter{}},
+		{"md", &MarkdownFormatter{}},
 		{"txt", &TextFormatter{}},
+		{"text", &TextFormatter{}},
 		{"csv", &CSVFormatter{}},
 		{"pdf", &PDFFormatter{}},
-		{"unknown", &MarkdownFormatter{}}, // default fallback
+		{"json", &JSONFormatter{}},
+		{"html", &HTMLFormatter{}},
+		{"yaml", &YAMLFormatter{}},
+		{"xlsx", &XLSXFormatter{}},
+		{"template", &TemplateFormatter{}},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.format, func(t *testing.T) {
-			formatter := GetFormatter(tt.format)
+			formatter, err := GetFormatter(tt.format, Options{})
+			require.NoError(t, err)
 			assert.IsType(t, tt.expected, formatter)
 		})
 	}
+
+	// Unknown formats are rejected instead of falling back to markdown
+	_, err := GetFormatter("unknown", Options{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "supported: "+strings.Join(Names(), ", "))
+}
+
+func TestRegistry(t *testing.T) {
+	// sqlite is only registered in cgo builds, see sqlite_test.go
+	names := Names()
+	assert.True(t, sort.StringsAreSorted(names))
+	assert.Subset(t, names, []string{"csv", "html", "json", "markdown", "pdf", "template", "txt", "xlsx", "yaml"})
+
+	tests := []struct {
+		ext      string
+		expected string
+	}{
+		{".pdf", "pdf"},
+		{".PDF", "pdf"},
+		{".md", "markdown"},
+		{".markdown", "markdown"},
+		{".txt", "txt"},
+		{".text", "txt"},
+		{".json", "json"},
+		{".html", "html"},
+		{".htm", "html"},
+		{".yaml", "yaml"},
+		{".yml", "yaml"},
+		{".xlsx", "xlsx"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ext, func(t *testing.T) {
+			format, ok := ByExtension(tt.ext)
+			require.True(t, ok)
+			assert.Equal(t, tt.expected, format.Name)
+		})
+	}
+
+	_, ok := ByExtension(".docx")
+	assert.False(t, ok)
+	_, ok = ByExtension("")
+	assert.False(t, ok)
+
+	format, ok := Lookup("pdf")
+	require.True(t, ok)
+	assert.Equal(t, "application/pdf", format.MIMEType)
+
+	assert.Panics(t, func() {
+		Register(Format{Name: "md", New: func(Options) Formatter { return &MarkdownFormatter{} }})
+	})
 }
 
 func TestMarkdownFormatter(t *testing.T) {
@@ -54,6 +121,67 @@ func TestMarkdownFormatter(t *testing.T) {
 	assert.Contains(t, output, "PostgreSQL")
 	assert.Contains(t, output, "Node.js")
 	assert.Contains(t, output, "Python")
+
+	// Check for addon plan pricing and features
+	assert.Contains(t, output, "| `prod` | 20.00€/month | Disk size: 10 GB, Max connection limit: 75, Backups: Daily - kept 7 days |")
+	assert.Contains(t, output, "  - Max connection limit: 75\n")
+	assert.Contains(t, output, "  - Zones: par, rbx, mtl\n")
+
+	// Check for addon provider metadata
+	assert.Contains(t, output, "### Redis (`redis`)\n\n*In-memory key-value store*\n\nRedis is an open source, in-memory data structure store.\n\n")
+	assert.Contains(t, output, "**Status**: release\n")
+	assert.Contains(t, output, "**Website**: https://redis.io\n")
+	assert.Contains(t, output, "**Regions**: par, rbx\n")
+	assert.Contains(t, output, "**Upgrade/downgrade**: Yes\n")
+	assert.Contains(t, output, "**Status**: beta\n")
+
+	// Check for the zone matrix
+	assert.Contains(t, output, "## Zone Availability")
+	assert.Contains(t, output, "| Addon plan | mtl | par | rbx |\n")
+	assert.Contains(t, output, "| `redis/large` |  | ✓ |  |\n")
+	assert.NotContains(t, output, "| `node` |  |")
+
+	// Check for normalized memory and disk sizes
+	assert.Contains(t, output, "| `nano` | `nano` | 256 MiB | - | 1 |")
+	assert.Contains(t, output, "- **small** - 512 MiB, 1 CPU, 10 GiB disk, 0.04€/h")
+}
+
+func TestZoneMatrixUnknown(t *testing.T) {
+	providers := fixtures.TestAddonProviders()
+	providers[1].Plans[1].Zones = nil
+
+	// The postgresql plans have no zones and their provider no regions either
+	matrix := buildZoneMatrix(providers)
+	require.Len(t, matrix.Unknown, 2)
+	assert.Equal(t, "Availability per zone is not published for: postgresql/dev, postgresql/prod", matrix.UnknownNote())
+
+	var buf bytes.Buffer
+	require.NoError(t, (&MarkdownFormatter{}).Format(providers, fixtures.TestProductInstances(), &buf))
+	assert.NotContains(t, buf.String(), "| `postgresql/prod` |")
+	assert.Contains(t, buf.String(), "*Availability per zone is not published for: postgresql/dev, postgresql/prod.*\n")
+}
+
+func TestPlanZonesFallback(t *testing.T) {
+	// Plans without zones are listed in the regions of their provider in
+	// every output, like in the zone matrix and with --zone
+	providers := fixtures.TestAddonProviders()
+	providers[0].Plans[1].Zones = nil // redis large
+
+	var buf bytes.Buffer
+	require.NoError(t, (&MarkdownFormatter{}).Format(providers, nil, &buf))
+	assert.Contains(t, buf.String(), "- **Large Redis** (`large`) - ID: `redis_large` - 40.00€/month\n  - Memory: 4 GB\n  - Zones: par, rbx\n")
+
+	buf.Reset()
+	require.NoError(t, (&TextFormatter{}).Format(providers, nil, &buf))
+	assert.Equal(t, 2, strings.Count(buf.String(), "Zones: par, rbx\n"))
+
+	buf.Reset()
+	require.NoError(t, (&CSVFormatter{}).Format(providers, nil, &buf))
+	assert.Contains(t, buf.String(), "redis_large,Large Redis,large,40.00,Memory: 4 GB,par|rbx\n")
+
+	buf.Reset()
+	require.NoError(t, (&CSVFormatter{Layout: CSVLayoutFlat}).Format(providers, nil, &buf))
+	assert.Contains(t, buf.String(), ",Memory: 4 GB,par|rbx,,\n")
 }
 
 func TestTextFormatter(t *testing.T) {
@@ -80,6 +208,17 @@ func TestTextFormatter(t *testing.T) {
 	assert.Contains(t, output, "PostgreSQL")
 	assert.Contains(t, output, "Node.js")
 	assert.Contains(t, output, "Python")
+
+	// Check for addon plan pricing and features
+	assert.Contains(t, output, "- Small Redis (small) - ID: redis_small - 5.00€/month\n    Memory: 256 MB\n")
+
+	// Check for addon provider metadata
+	assert.Contains(t, output, "In-memory key-value store\n")
+	assert.Contains(t, output, "Support: support@example.com\n")
+	assert.Contains(t, output, "Logo: https://example.com/redis.svg\n")
+
+	// Check for normalized memory and disk sizes
+	assert.Contains(t, output, "- small - 512 MiB, 1 CPU, 10 GiB disk, 0.04€/h")
 }
 
 func TestCSVFormatter(t *testing.T) {
@@ -106,6 +245,143 @@ func TestCSVFormatter(t *testing.T) {
 	assert.Contains(t, output, "postgresql,PostgreSQL")
 	assert.Contains(t, output, "node,Node.js")
 	assert.Contains(t, output, "python,Python")
+
+	// Check for addon plan pricing and features
+	assert.Contains(t, output, "Plan_Price,Plan_Features,Plan_Zones")
+	assert.Contains(t, output, "addon,redis,Redis,redis_large,Large Redis,large,40.00,Memory: 4 GB,par\n")
+
+	// Check for the zone matrix
+	assert.Contains(t, output, "# ZONE AVAILABILITY\nAddon_Plan,mtl,par,rbx\n")
+	assert.Contains(t, output, "redis/small,false,true,true\n")
+	assert.Contains(t, output, "postgresql/dev,,,\n")
+
+	// Check for sizes in bytes, with an empty cell for unknown disk sizes
+	assert.Contains(t, output, "Memory_Formatted,Memory_Bytes,Disk_Formatted,Disk_Bytes")
+	assert.Contains(t, output, ",nano,nano,256 MiB,268435456,,,1,")
+	assert.Contains(t, output, ",small,small,512 MiB,536870912,10 GiB,10737418240,1,")
+}
+
+func TestCSVFormatterFlat(t *testing.T) {
+	formatter := &CSVFormatter{Layout: CSVLayoutFlat, Delimiter: ';', Decimal: ","}
+	var buf bytes.Buffer
+
+	err := formatter.Format(fixtures.TestAddonProviders(), fixtures.TestProductInstances(), &buf)
+	require.NoError(t, err)
+
+	output := buf.String()
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+
+	// A single header row, without comment rows
+	assert.NotContains(t, output, "#")
+	assert.True(t, strings.HasPrefix(lines[0], "kind;id;name;version;enabled;item_id;"))
+	for _, line := range lines[1:] {
+		assert.Regexp(t, `^(addon|application);`, line)
+		assert.Equal(t, strings.Count(lines[0], ";"), strings.Count(line, ";"), line)
+	}
+
+	// Prices use the decimal separator, sizes stay in bytes
+	assert.Contains(t, output, "addon;redis;Redis;;;redis_large;Large Redis;large;40,00;month;")
+	assert.Contains(t, output, ";nano;nano;0,02;hour;268435456;;1;")
+}
+
+func TestCSVFormatterSplit(t *testing.T) {
+	formatter := &CSVFormatter{Layout: CSVLayoutSplit}
+	dir := t.TempDir()
+
+	// The split layout needs a directory
+	err := formatter.Format(fixtures.TestAddonProviders(), fixtures.TestProductInstances(), &bytes.Buffer{})
+	require.Error(t, err)
+
+	paths, err := formatter.FormatDirectory(fixtures.TestAddonProviders(), fixtures.TestProductInstances(), dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "addons.csv"),
+		filepath.Join(dir, "instances.csv"),
+		filepath.Join(dir, "zones.csv"),
+	}, paths)
+
+	addons, err := os.ReadFile(paths[0])
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(addons), "Type,Provider_ID,Provider_Name,"))
+	assert.NotContains(t, string(addons), "#")
+	assert.NotContains(t, string(addons), "Instance_Type")
+
+	instances, err := os.ReadFile(paths[1])
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(instances), "Type,Instance_Type,Instance_Name,"))
+	assert.Equal(t, 1, strings.Count(string(instances), "Type,Instance_Type"))
+}
+
+func TestCheckCSVOptions(t *testing.T) {
+	require.NoError(t, CheckCSVOptions(Options{}))
+	require.NoError(t, CheckCSVOptions(Options{CSVLayout: CSVLayoutFlat, CSVDelimiter: '\t', CSVDecimal: ","}))
+
+	tests := []struct {
+		name     string
+		opts     Options
+		expected string
+	}{
+		{"layout", Options{CSVLayout: "wide"}, "unsupported CSV layout"},
+		{"decimal", Options{CSVDecimal: "'"}, "unsupported CSV decimal separator"},
+		{"delimiter", Options{CSVDelimiter: '"'}, "invalid CSV delimiter"},
+		{"same", Options{CSVDelimiter: ',', CSVDecimal: ","}, "must differ"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckCSVOptions(tt.opts)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
+
+func TestHTMLFormatter(t *testing.T) {
+	formatter := &HTMLFormatter{}
+	var buf bytes.Buffer
+
+	providers := fixtures.TestAddonProviders()
+	instances := fixtures.TestProductInstances()
+	providers[0].Name = "Redis <Cache>"
+
+	err := formatter.Format(providers, instances, &buf)
+	require.NoError(t, err)
+
+	output := buf.String()
+
+	// Check for a self-contained page with the Markdown report sections
+	assert.True(t, strings.HasPrefix(output, "<!DOCTYPE html>"))
+	assert.Contains(t, output, "<style>")
+	assert.Contains(t, output, "<script>")
+	assert.NotContains(t, output, "src=\"http")
+	assert.NotContains(t, output, "href=\"http")
+	for _, section := range []string{"Addon Summary", "Application Summary", "Detailed Addon Plans",
+		"Detailed Application Flavors", "Plans by Addon Provider", "Flavors by Application Type", "Zone Availability"} {
+		assert.Contains(t, output, "<h2>"+section+"</h2>")
+	}
+
+	// Check for escaping and test data
+	assert.Contains(t, output, "Redis &lt;Cache&gt;")
+	assert.Contains(t, output, "PostgreSQL")
+	assert.Contains(t, output, "<dt>Website</dt><dd>https://redis.io</dd>")
+
+	// Check for sortable numeric cells and the search and toggle controls
+	assert.Contains(t, output, `<td class="number" data-value="20">20.00€/month</td>`)
+	assert.Contains(t, output, `data-value="536870912">512 MiB</td><td class="number" data-value="10737418240">10 GiB</td>`)
+	assert.Contains(t, output, `id="search"`)
+
+	// Toggles are only offered when there is something to reveal
+	assert.NotContains(t, output, `id="show-disabled"`)
+	assert.NotContains(t, output, `id="show-unavailable"`)
+
+	instances[0].Enabled = false
+	instances[1].Flavors[0].Available = false
+	buf.Reset()
+	require.NoError(t, formatter.Format(providers, instances, &buf))
+	output = buf.String()
+	assert.Contains(t, output, `id="show-disabled"`)
+	assert.Contains(t, output, `id="show-unavailable"`)
+	assert.Contains(t, output, `<article class="searchable disabled" id="instance-node">`)
 }
 
 func TestPDFFormatter(t *testing.T) {
@@ -122,27 +398,285 @@ func TestPDFFormatter(t *testing.T) {
 	output := buf.Bytes()
 	assert.True(t, len(output) > 0, "PDF should generate content")
 	assert.True(t, bytes.HasPrefix(output, []byte("%PDF")), "Output should be a valid PDF")
+
+	// Check that the bundled Unicode font is embedded
+	assert.Contains(t, string(output), "/FontFile2")
+}
+
+func TestPDFFormatterOutline(t *testing.T) {
+	formatter, err := GetFormatter("pdf", Options{Source: "snapshot catalog.json"})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, formatter.Format(fixtures.TestAddonProviders(), fixtures.TestProductInstances(), &buf))
+	output := buf.String()
+
+	// Sections, providers and application types are bookmarked and linked
+	// from the table of contents
+	assert.Contains(t, output, "/Outlines")
+	assert.Contains(t, output, "/Subtype /Link")
+	// The page count alias is replaced in the footers
+	assert.NotContains(t, output, "{nb}")
+}
+
+func TestPDFTOCPages(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	first, next := pdfTOCCapacity(pdf)
+	require.Greater(t, first, 0)
+	require.Greater(t, next, first)
+
+	assert.Equal(t, 1, pdfTOCPages(pdf, 0))
+	assert.Equal(t, 1, pdfTOCPages(pdf, first))
+	assert.Equal(t, 2, pdfTOCPages(pdf, first+1))
+	assert.Equal(t, 2, pdfTOCPages(pdf, first+next))
+	assert.Equal(t, 3, pdfTOCPages(pdf, first+next+1))
+}
+
+func TestPDFFormatterFont(t *testing.T) {
+	providers := fixtures.TestAddonProviders()
+	instances := fixtures.TestProductInstances()
+	providers[0].LongDesc = "Хранилище ключей – Ελληνικά – 5€"
+
+	// A user-supplied TrueType font replaces the bundled one
+	fontPath := filepath.Join(t.TempDir(), "Custom.ttf")
+	require.NoError(t, os.WriteFile(fontPath, pdfFontRegular, 0o644))
+
+	formatter, err := GetFormatter("pdf", Options{PDFFont: fontPath})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, formatter.Format(providers, instances, &buf))
+	assert.True(t, bytes.HasPrefix(buf.Bytes(), []byte("%PDF")))
+
+	// Missing font files are reported
+	formatter = &PDFFormatter{FontPath: filepath.Join(t.TempDir(), "missing.ttf")}
+	assert.ErrorContains(t, formatter.Format(providers, instances, &buf), "failed to read PDF font")
+}
+
+func TestJSONFormatter(t *testing.T) {
+	formatter := &JSONFormatter{}
+	var buf bytes.Buffer
+
+	providers := fixtures.TestAddonProviders()
+	instances := fixtures.TestProductInstances()
+
+	err := formatter.Format(providers, instances, &buf)
+	require.NoError(t, err)
+
+	var doc JSONDocument
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+
+	assert.Equal(t, clevercloud.JSONSchemaVersion, doc.SchemaVersion)
+	assert.Equal(t, ToolVersion, doc.ToolVersion)
+	assert.False(t, doc.GeneratedAt.IsZero())
+	assert.Equal(t, providers, doc.Providers)
+	assert.Equal(t, instances, doc.Instances)
+
+	// Empty catalogs encode as empty lists, not null
+	buf.Reset()
+	require.NoError(t, formatter.Format(nil, nil, &buf))
+	assert.Contains(t, buf.String(), `"providers": []`)
+	assert.Contains(t, buf.String(), `"instances": []`)
+}
+
+func TestYAMLFormatter(t *testing.T) {
+	formatter, err := GetFormatter("yml", Options{})
+	require.NoError(t, err)
+
+	providers := fixtures.TestAddonProviders()
+	instances := fixtures.TestProductInstances()
+
+	var buf bytes.Buffer
+	require.NoError(t, formatter.Format(providers, instances, &buf))
+	output := buf.String()
+
+	// Keys follow the JSON document order, in block style
+	assert.True(t, strings.HasPrefix(output, "schema_version: \"2\"\ngenerated_at: "))
+	assert.Contains(t, output, "\nproviders:\n  - id: redis\n    name: Redis\n")
+	// Strings that would read back as numbers stay quoted
+	assert.Contains(t, output, "value: \"75\"\n")
+
+	// The document holds the same catalog as the JSON one
+	var generic any
+	require.NoError(t, yaml.Unmarshal(buf.Bytes(), &generic))
+	data, err := json.Marshal(generic)
+	require.NoError(t, err)
+
+	var doc JSONDocument
+	require.NoError(t, json.Unmarshal(data, &doc))
+	assert.Equal(t, clevercloud.JSONSchemaVersion, doc.SchemaVersion)
+	assert.Equal(t, providers, doc.Providers)
+	assert.Equal(t, instances, doc.Instances)
+}
+
+func TestYAMLFormatterAmbiguousStrings(t *testing.T) {
+	providers := fixtures.TestAddonProviders()
+	providers[0].Plans[0].Features = []clevercloud.AddonFeature{
+		{Name: "Backups", Type: "BOOLEAN", Value: "yes"},
+		{Name: "Replication", Type: "BOOLEAN", Value: "off"},
+		{Name: "Retention", Type: "DURATION", Value: "1:30"},
+		{Name: "Encryption", Type: "BOOLEAN", Value: "true"},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, (&YAMLFormatter{}).Format(providers, nil, &buf))
+	output := buf.String()
+
+	// Strings a YAML 1.1 parser would read as booleans or numbers stay quoted
+	assert.Contains(t, output, "value: \"yes\"\n")
+	assert.Contains(t, output, "value: \"off\"\n")
+	assert.Contains(t, output, "value: \"1:30\"\n")
+	assert.Contains(t, output, "value: \"true\"\n")
+	// while other strings are written plain
+	assert.Contains(t, output, "type: BOOLEAN\n")
+
+	var doc JSONDocument
+	var generic any
+	require.NoError(t, yaml.Unmarshal(buf.Bytes(), &generic))
+	data, err := json.Marshal(generic)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &doc))
+	assert.Equal(t, providers, doc.Providers)
 }
 
-func TestTruncateText(t *testing.T) {
+func TestXLSXFormatter(t *testing.T) {
+	formatter, err := GetFormatter("xlsx", Options{})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, formatter.Format(fixtures.TestAddonProviders(), fixtures.TestProductInstances(), &buf))
+
+	workbook, err := excelize.OpenReader(&buf)
+	require.NoError(t, err)
+	defer workbook.Close()
+
+	assert.Equal(t, []string{"Addon Summary", "Addon Plans", "Application Summary", "Flavors", "Zone Availability"},
+		workbook.GetSheetList())
+
+	// Header rows are frozen
+	panes, err := workbook.GetPanes("Flavors")
+	require.NoError(t, err)
+	assert.True(t, panes.Freeze)
+	assert.Equal(t, 1, panes.YSplit)
+
+	// Sections are tables of their own, without comment rows
+	rows, err := workbook.GetRows("Addon Plans", excelize.Options{RawCellValue: true})
+	require.NoError(t, err)
+	require.Len(t, rows, 5)
+	assert.Equal(t, "Price (€/month)", rows[0][5])
+	assert.Equal(t, []string{"redis", "Redis", "redis_large", "Large Redis", "large", "40", "Memory: 4 GB", "par"}, rows[1])
+
+	// Sizes, counts and prices are numbers, flags are booleans
+	rows, err = workbook.GetRows("Flavors", excelize.Options{RawCellValue: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Type", "Name", "Version", "Enabled", "Flavor", "Flavor Slug", "Memory (MiB)", "Disk (GiB)",
+		"CPUs", "GPUs", "Price (€/hour)", "Available", "Microservice", "Machine Learning", "Default"}, rows[0])
+	assert.Equal(t, []string{"node", "Node.js", "20", "1", "nano", "nano", "256", "", "1", "0", "0.02", "1", "1", "0", "1"}, rows[1])
+	assert.Equal(t, "10", rows[2][7])
+
+	cellType, err := workbook.GetCellType("Flavors", "D2")
+	require.NoError(t, err)
+	assert.Equal(t, excelize.CellTypeBool, cellType)
+	value, err := workbook.GetCellValue("Flavors", "K2")
+	require.NoError(t, err)
+	assert.Equal(t, "0.02", value)
+
+	// Whole sizes are shown without a trailing decimal separator
+	for _, cell := range []string{"G3", "H3"} {
+		styleID, err := workbook.GetCellStyle("Flavors", cell)
+		require.NoError(t, err)
+		style, err := workbook.GetStyle(styleID)
+		require.NoError(t, err)
+		require.NotNil(t, style.CustomNumFmt)
+		assert.Equal(t, "General", *style.CustomNumFmt, cell)
+	}
+}
+
+func TestTemplateFormatter(t *testing.T) {
+	dir := t.TempDir()
+	providers := fixtures.TestAddonProviders()
+	instances := fixtures.TestProductInstances()
+	instances[0].Flavors[0].Slug = ""
+
+	path := filepath.Join(dir, "report.md.tmpl")
+	require.NoError(t, os.WriteFile(path, []byte(`From {{.Source}}
+{{range sortProviders .Providers}}{{.Name}}:{{range plansByPrice .}} {{.Slug}}={{price .Price}}{{end}}
+{{end}}{{range enabled .Instances}}{{.Type}}:{{range available .Flavors}} {{slug .}}{{end}}
+{{end}}{{join .Zones ","}}
+`), 0o644))
+
+	formatter, err := GetFormatter("template", Options{Template: path, Source: "snapshot catalog.json"})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, formatter.Format(providers, instances, &buf))
+	assert.Equal(t, `From snapshot catalog.json
+PostgreSQL: dev=0.00 prod=20.00
+Redis: small=5.00 large=40.00
+node: price_nano_123 small
+python: small
+mtl,par,rbx
+`, buf.String())
+	assert.Equal(t, ".md", TemplateExtension(path))
+
+	// Plans without zones fall back to the provider regions, like --zone
+	providers[0].Plans[1].Zones = nil // large, only in par otherwise
+	path = filepath.Join(dir, "zone.txt.tmpl")
+	require.NoError(t, os.WriteFile(path, []byte(`{{range .Providers}}{{.ID}}:{{range plansInZone "rbx" .}} {{.Slug}}{{end}}
+{{end}}`), 0o644))
+
+	buf.Reset()
+	require.NoError(t, (&TemplateFormatter{Path: path}).Format(providers, instances, &buf))
+	assert.Equal(t, "redis: large small\npostgresql: dev prod\n", buf.String())
+
+	// HTML templates escape catalog values
+	providers[0].Name = "Redis <Cache>"
+	path = filepath.Join(dir, "report.html.tmpl")
+	require.NoError(t, os.WriteFile(path, []byte(`{{range .Providers}}<p>{{.Name}}</p>{{end}}`), 0o644))
+
+	buf.Reset()
+	require.NoError(t, (&TemplateFormatter{Path: path}).Format(providers, instances, &buf))
+	assert.Contains(t, buf.String(), "<p>Redis &lt;Cache&gt;</p>")
+
+	// Parse errors and a missing template are reported
+	path = filepath.Join(dir, "broken.tmpl")
+	require.NoError(t, os.WriteFile(path, []byte(`{{range .Providers}}`), 0o644))
+	assert.ErrorContains(t, CheckTemplate(path), "failed to parse template")
+	assert.ErrorContains(t, CheckTemplate(""), "requires a template file")
+	assert.Equal(t, ".txt", TemplateExtension(path))
+}
+
+func TestWrapText(t *testing.T) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	require.NoError(t, (&PDFFormatter{}).addFonts(pdf))
+	pdf.SetFont(pdfFontFamily, "", 10)
+
+	width := pdf.GetStringWidth("this is a very")
+
 	tests := []struct {
 		text     string
-		maxLen   int
-		expected string
+		expected []string
 	}{
-		{"short", 10, "short"},
-		{"this is a very long text", 10, "this is..."},
-		{"exact", 5, "exact"},
-		{"toolong", 5, "to..."},
-		{"x", 3, "x"},
-		{"toolong", 1, "."},
-		{"toolong", 0, ""},
+		{"short", []string{"short"}},
+		{"this is a very long text", []string{"this is a very", "long text"}},
+		{"first\nsecond", []string{"first", "second"}},
+		{"", []string{""}},
+		{"unbreakablewordthatislong", nil},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.text, func(t *testing.T) {
-			result := truncateText(tt.text, tt.maxLen)
-			assert.Equal(t, tt.expected, result)
+			lines := wrapText(pdf, tt.text, width)
+			if tt.expected != nil {
+				assert.Equal(t, tt.expected, lines)
+			}
+
+			// Lines never exceed the width and no text is lost
+			for _, line := range lines {
+				assert.LessOrEqual(t, pdf.GetStringWidth(line), width)
+			}
+			compact := strings.NewReplacer(" ", "", "\n", "")
+			assert.Equal(t, compact.Replace(tt.text), compact.Replace(strings.Join(lines, "")))
 		})
 	}
 }
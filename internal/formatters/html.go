@@ -0,0 +1,94 @@
+package formatters
+
+import (
+	_ "embed"
+	"html/template"
+	"io"
+	"sort"
+
+	"cc-plans-lister/pkg/clevercloud"
+)
+
+//go:embed html/report.html.tmpl
+var htmlTemplate string
+
+//go:embed html/report.css
+var htmlStyle string
+
+//go:embed html/report.js
+var htmlScript string
+
+// htmlReport renders the HTML page with the functions offered to custom
+// templates. Templates are parsed once; a parse error is a programming error
+// caught by the tests.
+var htmlReport = template.Must(template.New("report").Funcs(templateFuncs).Parse(htmlTemplate))
+
+// HTMLFormatter generates a self-contained HTML page with sortable, searchable tables
+type HTMLFormatter struct{}
+
+func init() {
+	Register(Format{
+		Name:        "html",
+		Aliases:     []string{"htm"},
+		Extension:   ".html",
+		MIMEType:    "text/html",
+		Description: "Interactive single-file HTML page",
+		New:         func(Options) Formatter { return &HTMLFormatter{} },
+	})
+}
+
+// htmlData is the data passed to the HTML template
+type htmlData struct {
+	ToolVersion string
+	Style       template.CSS
+	Script      template.JS
+	Providers   []clevercloud.AddonProvider
+	Instances   []clevercloud.ProductInstance
+	Matrix      zoneMatrix
+
+	// The toggles are only offered when there is something to show
+	HasDisabled    bool
+	HasUnavailable bool
+}
+
+// Format generates an HTML page for addon providers and product instances.
+// Disabled instances (only listed with --include-disabled) and unavailable
+// flavors are hidden until the matching toggle is checked.
+func (f *HTMLFormatter) Format(providers []clevercloud.AddonProvider, instances []clevercloud.ProductInstance, writer io.Writer) error {
+	data := htmlData{
+		ToolVersion: ToolVersion,
+		Style:       template.CSS(htmlStyle),
+		Script:      template.JS(htmlScript),
+		Providers:   providers,
+		Instances:   instances,
+		Matrix:      buildZoneMatrix(providers),
+	}
+	for _, instance := range instances {
+		data.HasDisabled = data.HasDisabled || !instance.Enabled
+		for _, flavor := range instance.Flavors {
+			data.HasUnavailable = data.HasUnavailable || !flavor.Available
+		}
+	}
+
+	return htmlReport.Execute(writer, data)
+}
+
+// sortedPlans returns the provider plans sorted by slug for consistent output
+func sortedPlans(provider clevercloud.AddonProvider) []clevercloud.AddonPlan {
+	plans := make([]clevercloud.AddonPlan, len(provider.Plans))
+	copy(plans, provider.Plans)
+	sort.Slice(plans, func(i, j int) bool {
+		return plans[i].Slug < plans[j].Slug
+	})
+	return plans
+}
+
+// sortedFlavors returns the instance flavors sorted by name for consistent output
+func sortedFlavors(instance clevercloud.ProductInstance) []clevercloud.Flavor {
+	flavors := make([]clevercloud.Flavor, len(instance.Flavors))
+	copy(flavors, instance.Flavors)
+	sort.Slice(flavors, func(i, j int) bool {
+		return flavors[i].Name < flavors[j].Name
+	})
+	return flavors
+}
@@ -0,0 +1,76 @@
+(function () {
+  "use strict";
+
+  // Sorting: clicking a header sorts the rows by that column. Numeric columns
+  // sort on the raw data-value of their cells; empty values always go last.
+  function cellValue(cell, numeric) {
+    if (!cell) {
+      return null;
+    }
+    var raw = cell.dataset.value !== undefined ? cell.dataset.value : cell.textContent.trim();
+    if (!numeric) {
+      return raw.toLowerCase();
+    }
+    if (raw === "") {
+      return null;
+    }
+    var value = parseFloat(raw);
+    return isNaN(value) ? null : value;
+  }
+
+  document.querySelectorAll("table.sortable").forEach(function (table) {
+    var headers = table.querySelectorAll("thead th");
+    headers.forEach(function (header, index) {
+      header.addEventListener("click", function () {
+        var numeric = header.dataset.sort === "number";
+        var ascending = header.getAttribute("aria-sort") !== "ascending";
+        headers.forEach(function (other) {
+          other.removeAttribute("aria-sort");
+        });
+        header.setAttribute("aria-sort", ascending ? "ascending" : "descending");
+
+        var body = table.tBodies[0];
+        var rows = Array.prototype.slice.call(body.rows);
+        rows.sort(function (a, b) {
+          var x = cellValue(a.cells[index], numeric);
+          var y = cellValue(b.cells[index], numeric);
+          if (x === null || y === null) {
+            return (x === null) - (y === null);
+          }
+          var order = numeric ? x - y : x.localeCompare(y);
+          return ascending ? order : -order;
+        });
+        rows.forEach(function (row) {
+          body.appendChild(row);
+        });
+      });
+    });
+  });
+
+  // Search: hides table rows and sections that do not contain every word
+  var search = document.getElementById("search");
+  var searchable = document.querySelectorAll(".searchable");
+  search.addEventListener("input", function () {
+    var words = search.value.toLowerCase().split(/\s+/).filter(Boolean);
+    searchable.forEach(function (element) {
+      var text = element.textContent.toLowerCase();
+      var match = words.every(function (word) {
+        return text.indexOf(word) !== -1;
+      });
+      element.classList.toggle("filtered-out", !match);
+    });
+  });
+
+  // Toggles: disabled instances and unavailable flavors are hidden by default,
+  // and the toggles are left out of reports without any
+  ["show-disabled", "show-unavailable"].forEach(function (name) {
+    var checkbox = document.getElementById(name);
+    if (!checkbox) {
+      return;
+    }
+    checkbox.addEventListener("change", function () {
+      document.body.classList.toggle(name, checkbox.checked);
+    });
+    document.body.classList.toggle(name, checkbox.checked);
+  });
+})();
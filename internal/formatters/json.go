@@ -0,0 +1,62 @@
+package formatters
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"cc-plans-lister/pkg/clevercloud"
+)
+
+// ToolVersion is the cc-plans-lister version embedded in generated documents
+var ToolVersion = "dev"
+
+// JSONDocument is the top-level document emitted by JSONFormatter
+type JSONDocument struct {
+	SchemaVersion string                        `json:"schema_version"`
+	GeneratedAt   time.Time                     `json:"generated_at"`
+	ToolVersion   string                        `json:"tool_version"`
+	Providers     []clevercloud.AddonProvider   `json:"providers"`
+	Instances     []clevercloud.ProductInstance `json:"instances"`
+}
+
+// JSONFormatter generates a single JSON document
+type JSONFormatter struct{}
+
+func init() {
+	Register(Format{
+		Name:        "json",
+		Extension:   ".json",
+		MIMEType:    "application/json",
+		Description: "Machine-readable JSON document",
+		New:         func(Options) Formatter { return &JSONFormatter{} },
+	})
+}
+
+// Format generates a JSON document for addon providers and product instances
+func (f *JSONFormatter) Format(providers []clevercloud.AddonProvider, instances []clevercloud.ProductInstance, writer io.Writer) error {
+	doc := newJSONDocument(providers, instances)
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(doc)
+}
+
+// newJSONDocument builds the document, making sure empty lists are encoded
+// as [] rather than null so consumers do not need to special-case them
+func newJSONDocument(providers []clevercloud.AddonProvider, instances []clevercloud.ProductInstance) JSONDocument {
+	if providers == nil {
+		providers = []clevercloud.AddonProvider{}
+	}
+	if instances == nil {
+		instances = []clevercloud.ProductInstance{}
+	}
+
+	return JSONDocument{
+		SchemaVersion: clevercloud.JSONSchemaVersion,
+		GeneratedAt:   time.Now().UTC().Truncate(time.Second),
+		ToolVersion:   ToolVersion,
+		Providers:     providers,
+		Instances:     instances,
+	}
+}
@@ -12,6 +12,17 @@ import (
 // MarkdownFormatter generates markdown output
 type MarkdownFormatter struct{}
 
+func init() {
+	Register(Format{
+		Name:        "markdown",
+		Aliases:     []string{"md"},
+		Extension:   ".md",
+		MIMEType:    "text/markdown",
+		Description: "Markdown report with tables",
+		New:         func(Options) Formatter { return &MarkdownFormatter{} },
+	})
+}
+
 // Format generates a complete markdown table for addon providers and product instances
 func (f *MarkdownFormatter) Format(providers []clevercloud.AddonProvider, instances []clevercloud.ProductInstance, writer io.Writer) error {
 	var builder strings.Builder
@@ -46,12 +57,12 @@ func (f *MarkdownFormatter) Format(providers []clevercloud.AddonProvider, instan
 
 	// Detailed addon plans table
 	builder.WriteString("\n## Detailed Addon Plans\n\n")
-	builder.WriteString("| Provider ID | Provider Name | Plan ID | Plan Name | Plan Slug |\n")
-	builder.WriteString("|-------------|---------------|---------|-----------|----------|\n")
+	builder.WriteString("| Provider ID | Provider Name | Plan ID | Plan Name | Plan Slug | Price | Features |\n")
+	builder.WriteString("|-------------|---------------|---------|-----------|----------|-------|----------|\n")
 
 	for _, provider := range providers {
 		if len(provider.Plans) == 0 {
-			builder.WriteString(fmt.Sprintf("| `%s` | %s | - | No plans available | - |\n",
+			builder.WriteString(fmt.Sprintf("| `%s` | %s | - | No plans available | - | - | - |\n",
 				provider.ID, provider.Name))
 			continue
 		}
@@ -71,23 +82,24 @@ func (f *MarkdownFormatter) Format(providers []clevercloud.AddonProvider, instan
 				nameCell = provider.Name
 			}
 
-			builder.WriteString(fmt.Sprintf("| %s | %s | `%s` | %s | `%s` |\n",
-				providerCell, nameCell, plan.ID, plan.Name, plan.Slug))
+			features := formatPlanFeatures(plan, ", ")
+			if features == "" {
+				features = "-"
+			}
+
+			builder.WriteString(fmt.Sprintf("| %s | %s | `%s` | %s | `%s` | %.2f€/month | %s |\n",
+				providerCell, nameCell, plan.ID, plan.Name, plan.Slug, plan.Price, features))
 		}
 	}
 
 	// Detailed application flavors table
 	builder.WriteString("\n## Detailed Application Flavors\n\n")
-	builder.WriteString("| Type | Name | Flavor | Flavor Slug | Memory | CPU | Price | Available | Microservice | ML |\n")
-	builder.WriteString("|------|------|--------|-------------|--------|-----|-------|-----------|-------------|----|\n")
+	builder.WriteString("| Type | Name | Flavor | Flavor Slug | Memory | Disk | CPU | Price | Available | Microservice | ML |\n")
+	builder.WriteString("|------|------|--------|-------------|--------|------|-----|-------|-----------|-------------|----|\n")
 
 	for _, instance := range instances {
-		if !instance.Enabled {
-			continue // Skip disabled instances
-		}
-
 		if len(instance.Flavors) == 0 {
-			builder.WriteString(fmt.Sprintf("| `%s` | %s | - | - | - | - | - | - | - | - |\n",
+			builder.WriteString(fmt.Sprintf("| `%s` | %s | - | - | - | - | - | - | - | - | - |\n",
 				instance.Type, instance.Name))
 			continue
 		}
@@ -122,18 +134,8 @@ func (f *MarkdownFormatter) Format(providers []clevercloud.AddonProvider, instan
 				mlStr = "Yes"
 			}
 
-			// Use flavor slug if available, otherwise use PriceID or name as fallback
-			flavorSlug := flavor.Slug
-			if flavorSlug == "" {
-				if flavor.PriceID != "" {
-					flavorSlug = flavor.PriceID
-				} else {
-					flavorSlug = flavor.Name
-				}
-			}
-			
-			builder.WriteString(fmt.Sprintf("| %s | %s | `%s` | `%s` | %s | %d | %.2f€ | %s | %s | %s |\n",
-				typeCell, nameCell, flavor.Name, flavorSlug, flavor.Memory.Formatted, flavor.Cpus, flavor.Price, availableStr, microserviceStr, mlStr))
+			builder.WriteString(fmt.Sprintf("| %s | %s | `%s` | `%s` | %s | %s | %d | %.2f€ | %s | %s | %s |\n",
+				typeCell, nameCell, flavor.Name, flavor.EffectiveSlug(), flavor.MemorySize(), formatDisk(flavor), flavor.Cpus, flavor.Price, availableStr, microserviceStr, mlStr))
 		}
 	}
 
@@ -142,6 +144,16 @@ func (f *MarkdownFormatter) Format(providers []clevercloud.AddonProvider, instan
 	for _, provider := range providers {
 		builder.WriteString(fmt.Sprintf("### %s (`%s`)\n\n", provider.Name, provider.ID))
 
+		if provider.ShortDesc != "" {
+			builder.WriteString(fmt.Sprintf("*%s*\n\n", provider.ShortDesc))
+		}
+		if provider.LongDesc != "" {
+			builder.WriteString(provider.LongDesc + "\n\n")
+		}
+		for _, detail := range providerDetails(provider) {
+			builder.WriteString(fmt.Sprintf("**%s**: %s\n\n", detail.Label, detail.Value))
+		}
+
 		if len(provider.Plans) == 0 {
 			builder.WriteString("No plans available.\n\n")
 			continue
@@ -154,8 +166,14 @@ func (f *MarkdownFormatter) Format(providers []clevercloud.AddonProvider, instan
 		})
 
 		for _, plan := range plans {
-			builder.WriteString(fmt.Sprintf("- **%s** (`%s`) - ID: `%s`\n",
-				plan.Name, plan.Slug, plan.ID))
+			builder.WriteString(fmt.Sprintf("- **%s** (`%s`) - ID: `%s` - %.2f€/month\n",
+				plan.Name, plan.Slug, plan.ID, plan.Price))
+			for _, feature := range plan.Features {
+				builder.WriteString(fmt.Sprintf("  - %s: %s\n", feature.Name, feature.Value))
+			}
+			if zones := provider.PlanZones(plan); len(zones) > 0 {
+				builder.WriteString(fmt.Sprintf("  - Zones: %s\n", strings.Join(zones, ", ")))
+			}
 		}
 		builder.WriteString("\n")
 	}
@@ -163,10 +181,6 @@ func (f *MarkdownFormatter) Format(providers []clevercloud.AddonProvider, instan
 	// Applications by type section
 	builder.WriteString("\n## Flavors by Application Type\n\n")
 	for _, instance := range instances {
-		if !instance.Enabled {
-			continue // Skip disabled instances
-		}
-
 		builder.WriteString(fmt.Sprintf("### %s (`%s`) - Version %s\n\n", instance.Name, instance.Type, instance.Version))
 		builder.WriteString(fmt.Sprintf("**Description**: %s\n\n", instance.Description))
 		builder.WriteString(fmt.Sprintf("**Max instances**: %d\n\n", instance.MaxInstances))
@@ -191,8 +205,8 @@ func (f *MarkdownFormatter) Format(providers []clevercloud.AddonProvider, instan
 				defaultMarker = " *(default)*"
 			}
 
-			builder.WriteString(fmt.Sprintf("- **%s**%s - %s, %d CPU, %.2f€/h",
-				flavor.Name, defaultMarker, flavor.Memory.Formatted, flavor.Cpus, flavor.Price))
+			builder.WriteString(fmt.Sprintf("- **%s**%s - %s, %.2f€/h",
+				flavor.Name, defaultMarker, formatFlavorResources(flavor), flavor.Price))
 
 			var tags []string
 			if !flavor.Available {
@@ -213,6 +227,29 @@ func (f *MarkdownFormatter) Format(providers []clevercloud.AddonProvider, instan
 		builder.WriteString("\n")
 	}
 
+	// Zone availability matrix
+	matrix := buildZoneMatrix(providers)
+	if len(matrix.Zones) > 0 {
+		builder.WriteString("\n## Zone Availability\n\n")
+		builder.WriteString("| Addon plan | " + strings.Join(matrix.Zones, " | ") + " |\n")
+		builder.WriteString("|------------|" + strings.Repeat("-----|", len(matrix.Zones)) + "\n")
+
+		for _, row := range matrix.Rows {
+			builder.WriteString(fmt.Sprintf("| `%s` |", row.Item))
+			for _, zone := range matrix.Zones {
+				if row.Zones[zone] {
+					builder.WriteString(" ✓ |")
+				} else {
+					builder.WriteString("  |")
+				}
+			}
+			builder.WriteString("\n")
+		}
+		if note := matrix.UnknownNote(); note != "" {
+			builder.WriteString("\n*" + note + ".*\n")
+		}
+	}
+
 	_, err := writer.Write([]byte(builder.String()))
 	return err
 }
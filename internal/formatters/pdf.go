@@ -1,221 +1,364 @@
 package formatters
 
 import (
+	_ "embed"
 	"fmt"
 	"io"
-	"sort"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/jung-kurt/gofpdf"
 
 	"cc-plans-lister/pkg/clevercloud"
 )
 
+// pdfFontFamily is the name under which the report font is registered
+const pdfFontFamily = "DejaVu"
+
+// The bundled fonts cover Latin, Greek and Cyrillic scripts as well as the
+// euro sign and the symbols used in reports. See fonts/LICENSE.
+var (
+	//go:embed fonts/DejaVuSansCondensed.ttf
+	pdfFontRegular []byte
+	//go:embed fonts/DejaVuSansCondensed-Bold.ttf
+	pdfFontBold []byte
+	//go:embed fonts/DejaVuSansCondensed-Oblique.ttf
+	pdfFontItalic []byte
+)
+
 // PDFFormatter generates PDF output
-type PDFFormatter struct{}
+type PDFFormatter struct {
+	// FontPath is a TrueType font used for all text instead of the bundled
+	// DejaVu Sans Condensed; it is used for the bold and italic styles too
+	FontPath string
+	// Landscape lays out the wide application flavors table in landscape
+	Landscape bool
+	// Source is shown on the cover page as where the catalog comes from
+	Source string
+}
+
+func init() {
+	Register(Format{
+		Name:        "pdf",
+		Extension:   ".pdf",
+		MIMEType:    "application/pdf",
+		Description: "Printable PDF report",
+		New: func(opts Options) Formatter {
+			return &PDFFormatter{FontPath: opts.PDFFont, Landscape: opts.PDFLandscape, Source: opts.Source}
+		},
+	})
+}
 
-// Format generates PDF output for addon providers and product instances
+// Format generates PDF output for addon providers and product instances.
+// The report opens with a cover page and a table of contents linking to every
+// section, provider and application type, which are also PDF bookmarks.
 func (f *PDFFormatter) Format(providers []clevercloud.AddonProvider, instances []clevercloud.ProductInstance, writer io.Writer) error {
+	generatedAt := time.Now()
+
 	pdf := gofpdf.New("P", "mm", "A4", "")
-	pdf.AddPage()
+	// The page count alias must be set before the fonts are added
+	setPDFPageDecorations(pdf, generatedAt)
+	if err := f.addFonts(pdf); err != nil {
+		return err
+	}
+	pdf.SetTitle("Complete Clever Cloud Services Overview", true)
+	pdf.SetCreator("cc-plans-lister "+ToolVersion, true)
+	pdf.SetTopMargin(pdfTopMargin)
+	pdf.SetAutoPageBreak(true, pdfBottomMargin)
+
+	// Pages are added explicitly with their orientation so that tables
+	// continuing on a new page keep the orientation they started with
+	portraitPage := func() { pdf.AddPageFormat("P", pdf.GetPageSizeStr("A4")) }
+	flavorsPage := portraitPage
+	if f.Landscape {
+		flavorsPage = func() { pdf.AddPageFormat("L", pdf.GetPageSizeStr("A4")) }
+	}
 
-	// Set up fonts - gofpdf has built-in fonts
-	pdf.SetFont("Arial", "B", 16)
-	pdf.Cell(190, 10, "Complete Clever Cloud Services Overview")
-	pdf.Ln(15)
+	matrix := buildZoneMatrix(providers)
 
-	pdf.SetFont("Arial", "", 10)
-	pdf.Cell(190, 5, "Automatically generated via Clever Cloud API")
-	pdf.Ln(10)
+	portraitPage()
+	pdfCover(pdf, generatedAt, f.Source, len(providers), len(instances))
 
-	// Addon Summary Section
-	pdf.SetFont("Arial", "B", 14)
-	pdf.Cell(190, 10, "Addon Summary")
-	pdf.Ln(12)
+	// Reserve the table of contents pages, filled in once the page of every
+	// section is known: one entry per section, provider and instance
+	entries := 6 + len(providers) + len(instances)
+	if len(matrix.Zones) > 0 {
+		entries++
+	}
+	tocPage := pdf.PageNo() + 1
+	for i := 0; i < pdfTOCPages(pdf, entries); i++ {
+		portraitPage()
+	}
+	outline := &pdfOutline{pdf: pdf}
 
-	pdf.SetFont("Arial", "B", 10)
-	pdf.Cell(60, 8, "Provider ID")
-	pdf.Cell(80, 8, "Name")
-	pdf.Cell(30, 8, "Plans")
-	pdf.Ln(8)
+	portraitPage()
 
-	pdf.SetFont("Arial", "", 9)
+	// Addon Summary Section
+	pdfHeading(pdf, outline, "Addon Summary", portraitPage)
+	table := newPDFTable(pdf, 9, portraitPage,
+		pdfColumn{Header: "Provider ID", Width: 3},
+		pdfColumn{Header: "Name", Width: 5},
+		pdfColumn{Header: "Number of Plans", Width: 2, Align: "R"},
+	)
 	for _, provider := range providers {
-		pdf.Cell(60, 6, provider.ID)
-		pdf.Cell(80, 6, truncateText(provider.Name, 35))
-		pdf.Cell(30, 6, fmt.Sprintf("%d", len(provider.Plans)))
-		pdf.Ln(6)
+		table.Row(provider.ID, provider.Name, fmt.Sprintf("%d", len(provider.Plans)))
 	}
-
-	pdf.Ln(10)
+	pdf.Ln(6)
 
 	// Application Summary Section
-	pdf.SetFont("Arial", "B", 14)
-	pdf.Cell(190, 10, "Application Summary")
-	pdf.Ln(12)
-
-	pdf.SetFont("Arial", "B", 9)
-	pdf.Cell(25, 8, "Type")
-	pdf.Cell(45, 8, "Name")
-	pdf.Cell(20, 8, "Version")
-	pdf.Cell(20, 8, "Enabled")
-	pdf.Cell(20, 8, "Flavors")
-	pdf.Cell(30, 8, "Default")
-	pdf.Ln(8)
-
-	pdf.SetFont("Arial", "", 8)
+	pdfHeading(pdf, outline, "Application Summary", portraitPage)
+	table = newPDFTable(pdf, 9, portraitPage,
+		pdfColumn{Header: "Type", Width: 3},
+		pdfColumn{Header: "Name", Width: 5},
+		pdfColumn{Header: "Version", Width: 2},
+		pdfColumn{Header: "Enabled", Width: 2},
+		pdfColumn{Header: "Flavors", Width: 2, Align: "R"},
+		pdfColumn{Header: "Default Flavor", Width: 3},
+	)
 	for _, instance := range instances {
-		enabledStr := "No"
-		if instance.Enabled {
-			enabledStr = "Yes"
-		}
-
-		pdf.Cell(25, 6, truncateText(instance.Type, 12))
-		pdf.Cell(45, 6, truncateText(instance.Name, 20))
-		pdf.Cell(20, 6, truncateText(instance.Version, 10))
-		pdf.Cell(20, 6, enabledStr)
-		pdf.Cell(20, 6, fmt.Sprintf("%d", len(instance.Flavors)))
-		pdf.Cell(30, 6, truncateText(instance.DefaultFlavor.Name, 15))
-		pdf.Ln(6)
+		table.Row(instance.Type, instance.Name, instance.Version, pdfYesNo(instance.Enabled),
+			fmt.Sprintf("%d", len(instance.Flavors)), instance.DefaultFlavor.Name)
 	}
 
-	// Start new page for detailed information
-	pdf.AddPage()
-
 	// Detailed Addon Plans
-	pdf.SetFont("Arial", "B", 14)
-	pdf.Cell(190, 10, "Detailed Addon Plans")
-	pdf.Ln(12)
-
+	portraitPage()
+	pdfHeading(pdf, outline, "Detailed Addon Plans", portraitPage)
+	table = newPDFTable(pdf, 8, portraitPage,
+		pdfColumn{Header: "Provider", Width: 3},
+		pdfColumn{Header: "Plan ID", Width: 3},
+		pdfColumn{Header: "Plan Name", Width: 3},
+		pdfColumn{Header: "Plan Slug", Width: 2},
+		pdfColumn{Header: "Price", Width: 2, Align: "R"},
+		pdfColumn{Header: "Features", Width: 5},
+	)
 	for _, provider := range providers {
-		pdf.SetFont("Arial", "B", 11)
-		pdf.Cell(190, 8, fmt.Sprintf("%s (%s)", provider.Name, provider.ID))
-		pdf.Ln(8)
-
-		pdf.SetFont("Arial", "", 9)
+		providerCell := fmt.Sprintf("%s\n(%s)", provider.Name, provider.ID)
 		if len(provider.Plans) == 0 {
-			pdf.Cell(190, 6, "  No plans available")
-			pdf.Ln(6)
+			table.Row(providerCell, "-", "No plans available", "-", "-", "-")
 			continue
 		}
+		for _, plan := range sortedPlans(provider) {
+			table.Row(providerCell, plan.ID, plan.Name, plan.Slug,
+				fmt.Sprintf("%.2f€/month", plan.Price), formatPlanFeatures(plan, "\n"))
+		}
+	}
 
-		// Sort plans by slug for consistent output
-		plans := make([]clevercloud.AddonPlan, len(provider.Plans))
-		copy(plans, provider.Plans)
-		sort.Slice(plans, func(i, j int) bool {
-			return plans[i].Slug < plans[j].Slug
-		})
-
-		for _, plan := range plans {
-			text := fmt.Sprintf("  • %s (%s) - ID: %s", plan.Name, plan.Slug, plan.ID)
-			pdf.Cell(190, 6, truncateText(text, 80))
-			pdf.Ln(6)
+	// Detailed Application Flavors, optionally in landscape as the table is wide
+	flavorsPage()
+	pdfHeading(pdf, outline, "Detailed Application Flavors", flavorsPage)
+	table = newPDFTable(pdf, 8, flavorsPage,
+		pdfColumn{Header: "Type", Width: 3},
+		pdfColumn{Header: "Name", Width: 3},
+		pdfColumn{Header: "Flavor", Width: 2},
+		pdfColumn{Header: "Flavor Slug", Width: 3},
+		pdfColumn{Header: "Memory", Width: 2, Align: "R"},
+		pdfColumn{Header: "Disk", Width: 2, Align: "R"},
+		pdfColumn{Header: "CPU", Width: 1, Align: "R"},
+		pdfColumn{Header: "Price", Width: 2, Align: "R"},
+		pdfColumn{Header: "Available", Width: 2},
+		pdfColumn{Header: "Microservice", Width: 2},
+		pdfColumn{Header: "ML", Width: 1},
+	)
+	for _, instance := range instances {
+		if len(instance.Flavors) == 0 {
+			table.Row(instance.Type, instance.Name, "-", "-", "-", "-", "-", "-", "-", "-", "-")
+			continue
+		}
+		for _, flavor := range sortedFlavors(instance) {
+			table.Row(instance.Type, instance.Name, flavor.Name, flavor.EffectiveSlug(),
+				flavor.MemorySize().String(), formatDisk(flavor), fmt.Sprintf("%d", flavor.Cpus),
+				fmt.Sprintf("%.2f€", flavor.Price), pdfYesNo(flavor.Available),
+				pdfYesNo(flavor.Microservice), pdfYesNo(flavor.MachineLearning))
 		}
-		pdf.Ln(4)
 	}
 
-	// Start new page for application flavors
-	pdf.AddPage()
+	// Plans by Addon Provider
+	portraitPage()
+	pdfHeading(pdf, outline, "Plans by Addon Provider", portraitPage)
+	for _, provider := range providers {
+		pdfSubheading(pdf, outline, fmt.Sprintf("%s (%s)", provider.Name, provider.ID), portraitPage)
 
-	// Detailed Application Flavors
-	pdf.SetFont("Arial", "B", 14)
-	pdf.Cell(190, 10, "Detailed Application Flavors")
-	pdf.Ln(12)
+		if provider.ShortDesc != "" {
+			pdf.SetFont(pdfFontFamily, "I", 9)
+			pdf.MultiCell(0, 5, provider.ShortDesc, "", "L", false)
+		}
+		pdf.SetFont(pdfFontFamily, "", 9)
+		if provider.LongDesc != "" {
+			pdf.MultiCell(0, 5, provider.LongDesc, "", "L", false)
+		}
+		for _, detail := range providerDetails(provider) {
+			pdf.MultiCell(0, 5, fmt.Sprintf("%s: %s", detail.Label, detail.Value), "", "L", false)
+		}
 
-	for _, instance := range instances {
-		if !instance.Enabled {
-			continue
+		if len(provider.Plans) == 0 {
+			pdf.MultiCell(0, 5, "No plans available.", "", "L", false)
+		}
+		for _, plan := range sortedPlans(provider) {
+			pdfBullet(pdf, 0, fmt.Sprintf("%s (%s) - ID: %s - %.2f€/month", plan.Name, plan.Slug, plan.ID, plan.Price))
+			for _, feature := range plan.Features {
+				pdfBullet(pdf, 1, fmt.Sprintf("%s: %s", feature.Name, feature.Value))
+			}
+			if zones := provider.PlanZones(plan); len(zones) > 0 {
+				pdfBullet(pdf, 1, "Zones: "+strings.Join(zones, ", "))
+			}
 		}
+		pdf.Ln(4)
+	}
 
-		pdf.SetFont("Arial", "B", 11)
-		title := fmt.Sprintf("%s (%s) - Version %s", instance.Name, instance.Type, instance.Version)
-		pdf.Cell(190, 8, title)
-		pdf.Ln(8)
+	// Flavors by Application Type
+	portraitPage()
+	pdfHeading(pdf, outline, "Flavors by Application Type", portraitPage)
+	for _, instance := range instances {
 
-		pdf.SetFont("Arial", "", 9)
-		pdf.Cell(190, 6, fmt.Sprintf("Description: %s", truncateText(instance.Description, 80)))
-		pdf.Ln(6)
-		pdf.Cell(190, 6, fmt.Sprintf("Max instances: %d", instance.MaxInstances))
-		pdf.Ln(6)
-		pdf.Cell(190, 6, fmt.Sprintf("Tags: %s", strings.Join(instance.Tags, ", ")))
-		pdf.Ln(6)
+		pdfSubheading(pdf, outline, fmt.Sprintf("%s (%s) - Version %s", instance.Name, instance.Type, instance.Version), portraitPage)
+
+		pdf.SetFont(pdfFontFamily, "", 9)
+		pdf.MultiCell(0, 5, fmt.Sprintf("Description: %s", instance.Description), "", "L", false)
+		pdf.MultiCell(0, 5, fmt.Sprintf("Max instances: %d", instance.MaxInstances), "", "L", false)
+		pdf.MultiCell(0, 5, fmt.Sprintf("Tags: %s", strings.Join(instance.Tags, ", ")), "", "L", false)
+		pdf.MultiCell(0, 5, fmt.Sprintf("Deployments: %s", strings.Join(instance.Deployments, ", ")), "", "L", false)
 
 		if len(instance.Flavors) == 0 {
-			pdf.Cell(190, 6, "  No flavors available")
-			pdf.Ln(6)
+			pdf.MultiCell(0, 5, "No flavors available.", "", "L", false)
+			pdf.Ln(4)
 			continue
 		}
 
-		// Sort flavors by name for consistent output
-		flavors := make([]clevercloud.Flavor, len(instance.Flavors))
-		copy(flavors, instance.Flavors)
-		sort.Slice(flavors, func(i, j int) bool {
-			return flavors[i].Name < flavors[j].Name
-		})
-
-		pdf.Cell(190, 6, "Available flavors:")
-		pdf.Ln(6)
-
-		for _, flavor := range flavors {
+		pdf.MultiCell(0, 5, "Available flavors:", "", "L", false)
+		for _, flavor := range sortedFlavors(instance) {
 			defaultMarker := ""
 			if flavor.Name == instance.DefaultFlavor.Name {
 				defaultMarker = " (default)"
 			}
 
-			// Use flavor slug if available, otherwise use PriceID or name as fallback
-			flavorSlug := flavor.Slug
-			if flavorSlug == "" {
-				if flavor.PriceID != "" {
-					flavorSlug = flavor.PriceID
-				} else {
-					flavorSlug = flavor.Name
-				}
-			}
-
-			availableStr := "Available"
+			var tags []string
 			if !flavor.Available {
-				availableStr = "Unavailable"
+				tags = append(tags, "Unavailable")
 			}
-
-			var features []string
 			if flavor.Microservice {
-				features = append(features, "Microservice")
+				tags = append(tags, "Microservice")
 			}
 			if flavor.MachineLearning {
-				features = append(features, "ML")
+				tags = append(tags, "ML")
 			}
 
-			featuresStr := ""
-			if len(features) > 0 {
-				featuresStr = fmt.Sprintf(" [%s]", strings.Join(features, ", "))
+			text := fmt.Sprintf("%s (%s)%s - %s, %.2f€/h", flavor.Name, flavor.EffectiveSlug(), defaultMarker,
+				formatFlavorResources(flavor), flavor.Price)
+			if len(tags) > 0 {
+				text += fmt.Sprintf(" [%s]", strings.Join(tags, ", "))
 			}
+			pdfBullet(pdf, 0, text)
+		}
+		pdf.Ln(4)
+	}
 
-			text := fmt.Sprintf("  • %s (%s)%s - %s, %d CPU, %.2f€/h - %s%s",
-				flavor.Name, flavorSlug, defaultMarker, flavor.Memory.Formatted, flavor.Cpus,
-				flavor.Price, availableStr, featuresStr)
-			pdf.Cell(190, 6, truncateText(text, 90))
-			pdf.Ln(6)
+	// Zone availability matrix
+	if len(matrix.Zones) > 0 {
+		portraitPage()
+		pdfHeading(pdf, outline, "Zone Availability", portraitPage)
+
+		columns := []pdfColumn{{Header: "Addon plan", Width: 4}}
+		for _, zone := range matrix.Zones {
+			columns = append(columns, pdfColumn{Header: zone, Width: 1, Align: "C"})
+		}
+		table = newPDFTable(pdf, 8, portraitPage, columns...)
+
+		for _, row := range matrix.Rows {
+			cells := []string{row.Item}
+			for _, zone := range matrix.Zones {
+				mark := ""
+				if row.Zones[zone] {
+					mark = "✓"
+				}
+				cells = append(cells, mark)
+			}
+			table.Row(cells...)
+		}
+		if note := matrix.UnknownNote(); note != "" {
+			pdf.Ln(4)
+			pdf.SetFont(pdfFontFamily, "I", 9)
+			pdf.MultiCell(0, 5, note+".", "", "L", false)
 		}
-		pdf.Ln(6)
 	}
 
+	outline.writeTOC(tocPage)
+
 	// Write PDF to writer
 	return pdf.Output(writer)
 }
 
-// truncateText truncates text to fit within specified length
-func truncateText(text string, maxLen int) string {
-	if maxLen <= 0 {
-		return ""
+// pdfHeading writes a section heading, on a new page when the current one
+// has no room left for the heading and the start of the section, and
+// bookmarks it as a top-level entry of the outline
+func pdfHeading(pdf *gofpdf.Fpdf, outline *pdfOutline, text string, addPage func()) {
+	pdfEnsureSpace(pdf, 30, addPage)
+	outline.add(text, 0)
+	pdf.SetFont(pdfFontFamily, "B", 14)
+	pdf.MultiCell(0, 8, text, "", "L", false)
+	pdf.Ln(3)
+}
+
+// pdfSubheading writes the heading of a provider or application type and
+// bookmarks it under the current section
+func pdfSubheading(pdf *gofpdf.Fpdf, outline *pdfOutline, text string, addPage func()) {
+	pdfEnsureSpace(pdf, 20, addPage)
+	outline.add(text, 1)
+	pdf.SetFont(pdfFontFamily, "B", 11)
+	pdf.MultiCell(0, 6, text, "", "L", false)
+	pdf.Ln(1)
+}
+
+// pdfEnsureSpace starts a new page unless height mm are left on the current one
+func pdfEnsureSpace(pdf *gofpdf.Fpdf, height float64, addPage func()) {
+	_, pageHeight := pdf.GetPageSize()
+	_, _, _, bottom := pdf.GetMargins()
+	if pdf.GetY()+height > pageHeight-bottom {
+		addPage()
 	}
-	if len(text) <= maxLen {
-		return text
+}
+
+// pdfBullet writes a wrapped bullet point at the given nesting level
+func pdfBullet(pdf *gofpdf.Fpdf, level int, text string) {
+	left, _, _, _ := pdf.GetMargins()
+	indent := 4 + 6*float64(level)
+	bullet := "•"
+	if level > 0 {
+		bullet = "–"
 	}
-	if maxLen <= 3 {
-		if maxLen == 1 {
-			return "."
+
+	pdf.SetFont(pdfFontFamily, "", 9)
+	pdf.SetX(left + indent)
+	pdf.CellFormat(4, 5, bullet, "", 0, "L", false, 0, "")
+	pdf.MultiCell(0, 5, text, "", "L", false)
+}
+
+// pdfYesNo renders a boolean table cell
+func pdfYesNo(value bool) string {
+	if value {
+		return "Yes"
+	}
+	return "No"
+}
+
+// addFonts registers the UTF-8 fonts used by the report, so that the euro
+// sign and non-Latin text render correctly. gofpdf core fonts are Latin-1 only.
+func (f *PDFFormatter) addFonts(pdf *gofpdf.Fpdf) error {
+	regular, bold, italic := pdfFontRegular, pdfFontBold, pdfFontItalic
+	if f.FontPath != "" {
+		data, err := os.ReadFile(f.FontPath)
+		if err != nil {
+			return fmt.Errorf("failed to read PDF font: %w", err)
 		}
-		return "..." // for maxLen 2 or 3
+		regular, bold, italic = data, data, data
+	}
+
+	pdf.AddUTF8FontFromBytes(pdfFontFamily, "", regular)
+	pdf.AddUTF8FontFromBytes(pdfFontFamily, "B", bold)
+	pdf.AddUTF8FontFromBytes(pdfFontFamily, "I", italic)
+	if err := pdf.Error(); err != nil {
+		return fmt.Errorf("failed to load PDF font: %w", err)
 	}
-	return text[:maxLen-3] + "..."
+
+	return nil
 }
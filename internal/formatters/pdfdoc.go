@@ -0,0 +1,176 @@
+package formatters
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+const (
+	// pdfTopMargin leaves room for the running header above the content, in mm
+	pdfTopMargin = 20.0
+	// pdfBottomMargin leaves room for the page number footer, in mm
+	pdfBottomMargin = 18.0
+	// pdfTOCLineHeight is the height of a table of contents entry, in mm
+	pdfTOCLineHeight = 6.0
+	// pdfTOCTitleHeight is the room taken by the table of contents title, in mm
+	pdfTOCTitleHeight = 14.0
+)
+
+// pdfOutlineEntry is a section of the report, linked from the table of contents
+type pdfOutlineEntry struct {
+	title string
+	level int // 0 for report sections, 1 for providers and application types
+	page  int
+	link  int
+}
+
+// pdfOutline records the sections of the report as they are written, adding
+// a PDF bookmark for each and collecting them for the table of contents
+type pdfOutline struct {
+	pdf     *gofpdf.Fpdf
+	entries []pdfOutlineEntry
+}
+
+// add bookmarks the current position under the given title
+func (o *pdfOutline) add(title string, level int) {
+	link := o.pdf.AddLink()
+	o.pdf.SetLink(link, o.pdf.GetY(), -1)
+	o.pdf.Bookmark(title, level, -1)
+
+	o.entries = append(o.entries, pdfOutlineEntry{
+		title: title,
+		level: level,
+		page:  o.pdf.PageNo(),
+		link:  link,
+	})
+}
+
+// pdfTOCCapacity returns how many table of contents entries fit on its first
+// page and on the following ones
+func pdfTOCCapacity(pdf *gofpdf.Fpdf) (first, next int) {
+	_, pageHeight := pdf.GetPageSize()
+	available := pageHeight - pdfTopMargin - pdfBottomMargin
+	return int((available - pdfTOCTitleHeight) / pdfTOCLineHeight), int(available / pdfTOCLineHeight)
+}
+
+// pdfTOCPages returns the number of pages needed to list the given number of
+// table of contents entries
+func pdfTOCPages(pdf *gofpdf.Fpdf, entries int) int {
+	first, next := pdfTOCCapacity(pdf)
+	if entries <= first {
+		return 1
+	}
+	return 1 + (entries-first+next-1)/next
+}
+
+// writeTOC fills the table of contents pages reserved from page first on,
+// then returns to the last page of the document. Entries link to their section.
+func (o *pdfOutline) writeTOC(first int) {
+	pdf := o.pdf
+	last := pdf.PageNo()
+	left, _, right, _ := pdf.GetMargins()
+	pageWidth, _ := pdf.GetPageSize()
+	width := pageWidth - left - right
+
+	auto, margin := pdf.GetAutoPageBreak()
+	pdf.SetAutoPageBreak(false, margin)
+	defer pdf.SetAutoPageBreak(auto, margin)
+
+	pdf.SetPage(first)
+	pdf.SetXY(left, pdfTopMargin)
+	pdf.SetFont(pdfFontFamily, "B", 16)
+	pdf.CellFormat(width, pdfTOCTitleHeight-4, "Table of Contents", "", 1, "L", false, 0, "")
+	pdf.Ln(4)
+
+	capacity, next := pdfTOCCapacity(pdf)
+	for i, entry := range o.entries {
+		if i == capacity {
+			first++
+			capacity += next
+			pdf.SetPage(first)
+			pdf.SetXY(left, pdfTopMargin)
+		}
+
+		indent := 8 * float64(entry.level)
+		style := "B"
+		if entry.level > 0 {
+			style = ""
+		}
+
+		pdf.SetFont(pdfFontFamily, style, 10)
+		pdf.SetX(left + indent)
+		pdf.CellFormat(width-indent-15, pdfTOCLineHeight, entry.title, "", 0, "L", false, entry.link, "")
+		pdf.CellFormat(15, pdfTOCLineHeight, fmt.Sprintf("%d", entry.page), "", 1, "R", false, entry.link, "")
+	}
+
+	pdf.SetPage(last)
+}
+
+// pdfCover writes the cover page
+func pdfCover(pdf *gofpdf.Fpdf, generatedAt time.Time, source string, providers, instances int) {
+	if source == "" {
+		source = "Clever Cloud API"
+	}
+
+	pdf.SetY(80)
+	pdf.SetFont(pdfFontFamily, "B", 26)
+	pdf.MultiCell(0, 12, "Complete Clever Cloud Services Overview", "", "C", false)
+	pdf.Ln(4)
+
+	pdf.SetFont(pdfFontFamily, "", 12)
+	pdf.MultiCell(0, 6, "All available addon types and application types on Clever Cloud with their respective plans and flavors", "", "C", false)
+	pdf.Ln(30)
+
+	details := []providerDetail{
+		{"Generated", generatedAt.UTC().Format("2006-01-02 15:04 MST")},
+		{"Tool version", "cc-plans-lister " + ToolVersion},
+		{"Source", source},
+		{"Contents", fmt.Sprintf("%d addon providers, %d application types", providers, instances)},
+	}
+	for _, detail := range details {
+		pdf.SetFont(pdfFontFamily, "B", 11)
+		pdf.CellFormat(60, 7, detail.Label+":", "", 0, "R", false, 0, "")
+		pdf.SetFont(pdfFontFamily, "", 11)
+		pdf.MultiCell(0, 7, "  "+detail.Value, "", "L", false)
+	}
+}
+
+// setPDFPageDecorations adds a running header with the report title and date,
+// and a "Page X of Y" footer, to every page but the cover
+func setPDFPageDecorations(pdf *gofpdf.Fpdf, generatedAt time.Time) {
+	pdf.AliasNbPages("")
+
+	pdf.SetHeaderFunc(func() {
+		if pdf.PageNo() == 1 {
+			return
+		}
+
+		left, _, right, _ := pdf.GetMargins()
+		pageWidth, _ := pdf.GetPageSize()
+
+		pdf.SetY(8)
+		pdf.SetFont(pdfFontFamily, "", 8)
+		pdf.SetTextColor(110, 110, 110)
+		pdf.CellFormat(0, 5, "Complete Clever Cloud Services Overview", "", 0, "L", false, 0, "")
+		pdf.CellFormat(0, 5, generatedAt.UTC().Format("2006-01-02"), "", 0, "R", false, 0, "")
+		pdf.SetDrawColor(200, 200, 200)
+		pdf.Line(left, 14, pageWidth-right, 14)
+		pdf.SetDrawColor(0, 0, 0)
+		pdf.SetTextColor(0, 0, 0)
+		pdf.SetY(pdfTopMargin)
+	})
+
+	pdf.SetFooterFunc(func() {
+		if pdf.PageNo() == 1 {
+			return
+		}
+
+		pdf.SetY(-12)
+		pdf.SetFont(pdfFontFamily, "", 8)
+		pdf.SetTextColor(110, 110, 110)
+		pdf.CellFormat(0, 5, fmt.Sprintf("Page %d of {nb}", pdf.PageNo()), "", 0, "C", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+	})
+}
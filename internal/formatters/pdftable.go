@@ -0,0 +1,173 @@
+package formatters
+
+import (
+	"strings"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+const (
+	// pdfLineHeight is the height of a line of table text, in mm
+	pdfLineHeight = 4.5
+	// pdfCellPadding is the vertical padding of table cells, in mm
+	pdfCellPadding = 1.0
+)
+
+// pdfColumn is a column of a PDF table. Widths are relative: they are scaled
+// to fill the width available on the page the table starts on.
+type pdfColumn struct {
+	Header string
+	Width  float64
+	Align  string // "L", "C" or "R"
+}
+
+// pdfTable draws a bordered, zebra-striped table whose cells wrap on several
+// lines. The header row is repeated at the top of every page the table spans.
+type pdfTable struct {
+	pdf      *gofpdf.Fpdf
+	columns  []pdfColumn
+	fontSize float64
+	addPage  func()
+	rows     int
+}
+
+// newPDFTable starts a table at the current position and draws its header.
+// addPage is called when a row does not fit on the current page, so that the
+// table continues on a page of the same orientation.
+func newPDFTable(pdf *gofpdf.Fpdf, fontSize float64, addPage func(), columns ...pdfColumn) *pdfTable {
+	pageWidth, _ := pdf.GetPageSize()
+	left, _, right, _ := pdf.GetMargins()
+
+	total := 0.0
+	for _, column := range columns {
+		total += column.Width
+	}
+
+	scaled := make([]pdfColumn, len(columns))
+	for i, column := range columns {
+		column.Width = column.Width * (pageWidth - left - right) / total
+		if column.Align == "" {
+			column.Align = "L"
+		}
+		scaled[i] = column
+	}
+
+	table := &pdfTable{pdf: pdf, columns: scaled, fontSize: fontSize, addPage: addPage}
+	table.header()
+	return table
+}
+
+// header draws the column headers
+func (t *pdfTable) header() {
+	headers := make([]string, len(t.columns))
+	for i, column := range t.columns {
+		headers[i] = column.Header
+	}
+
+	t.pdf.SetFont(pdfFontFamily, "B", t.fontSize)
+	t.pdf.SetFillColor(221, 227, 238)
+	t.draw(headers, true)
+	t.pdf.SetFont(pdfFontFamily, "", t.fontSize)
+}
+
+// Row draws a row, moving to a new page first (and repeating the header)
+// when it does not fit on the current one
+func (t *pdfTable) Row(cells ...string) {
+	t.pdf.SetFont(pdfFontFamily, "", t.fontSize)
+
+	_, pageHeight := t.pdf.GetPageSize()
+	_, _, _, bottom := t.pdf.GetMargins()
+	if t.pdf.GetY()+t.height(cells) > pageHeight-bottom {
+		t.addPage()
+		t.header()
+	}
+
+	t.pdf.SetFillColor(245, 246, 248)
+	t.draw(cells, t.rows%2 == 1)
+	t.rows++
+}
+
+// height returns the height of a row once its cells are wrapped
+func (t *pdfTable) height(cells []string) float64 {
+	lines := 1
+	for i, column := range t.columns {
+		if i < len(cells) {
+			if n := len(t.wrap(cells[i], column)); n > lines {
+				lines = n
+			}
+		}
+	}
+	return float64(lines)*pdfLineHeight + 2*pdfCellPadding
+}
+
+// wrap splits a cell into the lines fitting its column
+func (t *pdfTable) wrap(text string, column pdfColumn) []string {
+	return wrapText(t.pdf, text, column.Width-2*t.pdf.GetCellMargin())
+}
+
+// draw draws a row of cells at the current position, filled with the current
+// fill color when fill is set, and moves below it
+func (t *pdfTable) draw(cells []string, fill bool) {
+	left, _, _, _ := t.pdf.GetMargins()
+	y := t.pdf.GetY()
+	height := t.height(cells)
+
+	style := "D"
+	if fill {
+		style = "FD"
+	}
+
+	x := left
+	for i, column := range t.columns {
+		t.pdf.Rect(x, y, column.Width, height, style)
+		if i < len(cells) {
+			for j, line := range t.wrap(cells[i], column) {
+				t.pdf.SetXY(x, y+pdfCellPadding+float64(j)*pdfLineHeight)
+				t.pdf.CellFormat(column.Width, pdfLineHeight, line, "", 0, column.Align, false, 0, "")
+			}
+		}
+		x += column.Width
+	}
+
+	t.pdf.SetXY(left, y+height)
+}
+
+// wrapText splits text into lines fitting the given width with the current
+// font. Lines break at spaces and newlines; words are only split when they
+// are longer than a line on their own.
+func wrapText(pdf *gofpdf.Fpdf, text string, width float64) []string {
+	var lines []string
+
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if pdf.GetStringWidth(candidate) <= width {
+				line = candidate
+				continue
+			}
+
+			if line != "" {
+				lines = append(lines, line)
+			}
+
+			// Split words that do not fit on a line of their own
+			for len([]rune(word)) > 1 && pdf.GetStringWidth(word) > width {
+				runes := []rune(word)
+				n := len(runes) - 1
+				for n > 1 && pdf.GetStringWidth(string(runes[:n])) > width {
+					n--
+				}
+				lines = append(lines, string(runes[:n]))
+				word = string(runes[n:])
+			}
+			line = word
+		}
+		lines = append(lines, line)
+	}
+
+	return lines
+}
@@ -0,0 +1,119 @@
+package formatters
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DefaultFormat is the format used when none is given or implied
+const DefaultFormat = "markdown"
+
+// Format describes an output format and how to create its formatter
+type Format struct {
+	Name        string
+	Aliases     []string
+	Extension   string // file extension including the dot, e.g. ".md"
+	MIMEType    string
+	Description string
+	New         func(opts Options) Formatter
+}
+
+// registry holds the registered formats by name
+var registry = map[string]Format{}
+
+// Register makes a format available under its name and aliases. It is meant
+// to be called from the init function of the file implementing the formatter
+// and panics when a name or alias is already taken.
+func Register(format Format) {
+	if format.Name == "" || format.New == nil {
+		panic("formatters: Register requires a name and a constructor")
+	}
+
+	for _, name := range append([]string{format.Name}, format.Aliases...) {
+		if _, ok := Lookup(name); ok {
+			panic(fmt.Sprintf("formatters: format %q registered twice", name))
+		}
+	}
+
+	registry[format.Name] = format
+}
+
+// Lookup returns the format registered under the given name or alias
+func Lookup(name string) (Format, bool) {
+	if format, ok := registry[name]; ok {
+		return format, true
+	}
+
+	for _, format := range registry {
+		for _, alias := range format.Aliases {
+			if alias == name {
+				return format, true
+			}
+		}
+	}
+
+	return Format{}, false
+}
+
+// ByExtension returns the format producing files with the given extension
+// (".pdf", ".md", ...). The extension is matched case-insensitively, and also
+// against format names and aliases so that ".markdown" or ".text" work too.
+func ByExtension(ext string) (Format, bool) {
+	ext = strings.ToLower(ext)
+	if ext == "" || ext == "." {
+		return Format{}, false
+	}
+
+	for _, format := range Formats() {
+		if strings.ToLower(format.Extension) == ext {
+			return format, true
+		}
+	}
+
+	return Lookup(strings.TrimPrefix(ext, "."))
+}
+
+// Formats returns every registered format, sorted by name
+func Formats() []Format {
+	formats := make([]Format, 0, len(registry))
+	for _, format := range registry {
+		formats = append(formats, format)
+	}
+
+	sort.Slice(formats, func(i, j int) bool {
+		return formats[i].Name < formats[j].Name
+	})
+
+	return formats
+}
+
+// Names returns the names of every registered format, sorted
+func Names() []string {
+	formats := Formats()
+	names := make([]string, len(formats))
+	for i, format := range formats {
+		names[i] = format.Name
+	}
+	return names
+}
+
+// ParseFormat returns the format registered under the given name or alias,
+// with an error listing the supported formats when there is none
+func ParseFormat(name string) (Format, error) {
+	format, ok := Lookup(name)
+	if !ok {
+		return Format{}, fmt.Errorf("unsupported output format: %s (supported: %s)", name, strings.Join(Names(), ", "))
+	}
+	return format, nil
+}
+
+// GetFormatter returns a new formatter for the given format name or alias
+func GetFormatter(name string, opts Options) (Formatter, error) {
+	format, err := ParseFormat(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return format.New(opts), nil
+}
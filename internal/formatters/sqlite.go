@@ -0,0 +1,286 @@
+//go:build cgo
+
+// The go-sqlite3 driver is a cgo binding, so the sqlite format is only
+// registered in cgo builds and is missing from CGO_ENABLED=0 binaries.
+
+package formatters
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3" // registers the sqlite3 database/sql driver
+
+	"cc-plans-lister/pkg/clevercloud"
+)
+
+// SQLiteSchemaVersion is the version of the database layout produced by
+// SQLiteFormatter, stored in the metadata table and as the user_version
+// pragma. Like clevercloud.JSONSchemaVersion, it is bumped when a table or column is
+// renamed or removed.
+const SQLiteSchemaVersion = 2
+
+// sqliteSchema creates the tables of the catalog database. Booleans are
+// stored as 0/1 integers, sizes in bytes, addon plan prices in €/month and
+// flavor prices in €/hour.
+const sqliteSchema = `
+CREATE TABLE metadata (
+	key   TEXT PRIMARY KEY,
+	value TEXT NOT NULL
+);
+
+CREATE TABLE providers (
+	id            TEXT PRIMARY KEY,
+	name          TEXT NOT NULL,
+	short_desc    TEXT,
+	long_desc     TEXT,
+	website       TEXT,
+	support_email TEXT,
+	status        TEXT,
+	can_upgrade   INTEGER NOT NULL,
+	logo_url      TEXT
+);
+
+CREATE TABLE addon_plans (
+	id          INTEGER PRIMARY KEY,
+	provider_id TEXT NOT NULL REFERENCES providers(id) ON DELETE CASCADE,
+	plan_id     TEXT NOT NULL,
+	name        TEXT NOT NULL,
+	slug        TEXT NOT NULL,
+	price       REAL NOT NULL,
+	price_id    TEXT
+);
+CREATE INDEX addon_plans_provider_id ON addon_plans(provider_id);
+CREATE INDEX addon_plans_plan_id ON addon_plans(plan_id);
+CREATE INDEX addon_plans_price ON addon_plans(price);
+
+CREATE TABLE addon_plan_features (
+	addon_plan_id INTEGER NOT NULL REFERENCES addon_plans(id) ON DELETE CASCADE,
+	name          TEXT NOT NULL,
+	type          TEXT,
+	value         TEXT,
+	name_code     TEXT
+);
+CREATE INDEX addon_plan_features_addon_plan_id ON addon_plan_features(addon_plan_id);
+
+CREATE TABLE addon_plan_zones (
+	addon_plan_id INTEGER NOT NULL REFERENCES addon_plans(id) ON DELETE CASCADE,
+	zone          TEXT NOT NULL,
+	PRIMARY KEY (addon_plan_id, zone)
+);
+CREATE INDEX addon_plan_zones_zone ON addon_plan_zones(zone);
+
+CREATE TABLE instance_types (
+	id             INTEGER PRIMARY KEY,
+	type           TEXT NOT NULL,
+	version        TEXT,
+	name           TEXT NOT NULL,
+	variant_slug   TEXT,
+	deploy_type    TEXT,
+	description    TEXT,
+	enabled        INTEGER NOT NULL,
+	coming_soon    INTEGER NOT NULL,
+	max_instances  INTEGER,
+	default_flavor TEXT,
+	build_flavor   TEXT
+);
+CREATE INDEX instance_types_type ON instance_types(type);
+
+CREATE TABLE flavors (
+	id               INTEGER PRIMARY KEY,
+	instance_type_id INTEGER NOT NULL REFERENCES instance_types(id) ON DELETE CASCADE,
+	name             TEXT NOT NULL,
+	slug             TEXT NOT NULL,
+	price_id         TEXT,
+	memory_bytes     INTEGER NOT NULL,
+	disk_bytes       INTEGER,
+	cpus             INTEGER NOT NULL,
+	gpus             INTEGER NOT NULL,
+	price            REAL NOT NULL,
+	available        INTEGER NOT NULL,
+	microservice     INTEGER NOT NULL,
+	machine_learning INTEGER NOT NULL,
+	nice             INTEGER NOT NULL,
+	is_default       INTEGER NOT NULL
+);
+CREATE INDEX flavors_instance_type_id ON flavors(instance_type_id);
+CREATE INDEX flavors_memory_bytes ON flavors(memory_bytes);
+CREATE INDEX flavors_price ON flavors(price);
+
+CREATE TABLE instance_tags (
+	instance_type_id INTEGER NOT NULL REFERENCES instance_types(id) ON DELETE CASCADE,
+	tag              TEXT NOT NULL,
+	PRIMARY KEY (instance_type_id, tag)
+);
+CREATE INDEX instance_tags_tag ON instance_tags(tag);
+
+CREATE TABLE instance_deployments (
+	instance_type_id INTEGER NOT NULL REFERENCES instance_types(id) ON DELETE CASCADE,
+	deployment       TEXT NOT NULL,
+	PRIMARY KEY (instance_type_id, deployment)
+);
+CREATE INDEX instance_deployments_deployment ON instance_deployments(deployment);
+`
+
+// SQLiteFormatter generates a SQLite database with one normalized table per
+// kind of catalog item, for ad-hoc SQL queries
+type SQLiteFormatter struct{}
+
+func init() {
+	Register(Format{
+		Name:        "sqlite",
+		Aliases:     []string{"sqlite3", "db"},
+		Extension:   ".sqlite",
+		MIMEType:    "application/vnd.sqlite3",
+		Description: "SQLite database for ad-hoc SQL queries",
+		New:         func(Options) Formatter { return &SQLiteFormatter{} },
+	})
+}
+
+// Format generates a SQLite database for addon providers and product instances.
+// SQLite only works on files, so the database is built in a temporary file
+// which is then copied to the writer.
+func (f *SQLiteFormatter) Format(providers []clevercloud.AddonProvider, instances []clevercloud.ProductInstance, writer io.Writer) error {
+	dir, err := os.MkdirTemp("", "cc-plans-lister-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "catalog.sqlite")
+	if err := writeSQLite(path, providers, instances); err != nil {
+		return err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(writer, file)
+	return err
+}
+
+// writeSQLite creates the database at path and fills it in a single transaction
+func writeSQLite(path string, providers []clevercloud.AddonProvider, instances []clevercloud.ProductInstance) error {
+	db, err := sql.Open("sqlite3", "file:"+path+"?_foreign_keys=on")
+	if err != nil {
+		return fmt.Errorf("failed to open SQLite database: %w", err)
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to open SQLite database: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(sqliteSchema); err != nil {
+		return fmt.Errorf("failed to create SQLite schema: %w", err)
+	}
+
+	w := &sqliteWriter{tx: tx}
+	w.exec("INSERT INTO metadata (key, value) VALUES (?, ?), (?, ?), (?, ?)",
+		"schema_version", fmt.Sprint(SQLiteSchemaVersion),
+		"generated_at", time.Now().UTC().Truncate(time.Second).Format(time.RFC3339),
+		"tool_version", ToolVersion)
+	w.exec(fmt.Sprintf("PRAGMA user_version = %d", SQLiteSchemaVersion))
+
+	for _, provider := range providers {
+		w.provider(provider)
+	}
+	for _, instance := range instances {
+		w.instance(instance)
+	}
+	if w.err != nil {
+		return w.err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to write SQLite database: %w", err)
+	}
+	return db.Close()
+}
+
+// sqliteWriter inserts catalog items, keeping the first error so that the
+// callers do not need to check every statement
+type sqliteWriter struct {
+	tx  *sql.Tx
+	err error
+}
+
+// exec runs a statement unless a previous one failed, and returns the ID of
+// the inserted row
+func (w *sqliteWriter) exec(query string, args ...any) int64 {
+	if w.err != nil {
+		return 0
+	}
+
+	result, err := w.tx.Exec(query, args...)
+	if err != nil {
+		w.err = fmt.Errorf("failed to write SQLite database: %w", err)
+		return 0
+	}
+
+	id, _ := result.LastInsertId()
+	return id
+}
+
+// provider inserts an addon provider with its plans, their features and zones.
+// Plans without zones are available in the regions of their provider.
+func (w *sqliteWriter) provider(provider clevercloud.AddonProvider) {
+	w.exec(`INSERT INTO providers (id, name, short_desc, long_desc, website, support_email, status, can_upgrade, logo_url)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		provider.ID, provider.Name, provider.ShortDesc, provider.LongDesc, provider.Website,
+		provider.SupportEmail, provider.Status, provider.CanUpgrade, provider.LogoURL)
+
+	for _, plan := range provider.Plans {
+		id := w.exec("INSERT INTO addon_plans (provider_id, plan_id, name, slug, price, price_id) VALUES (?, ?, ?, ?, ?, ?)",
+			provider.ID, plan.ID, plan.Name, plan.Slug, plan.Price, plan.PriceID)
+
+		for _, feature := range plan.Features {
+			w.exec("INSERT INTO addon_plan_features (addon_plan_id, name, type, value, name_code) VALUES (?, ?, ?, ?, ?)",
+				id, feature.Name, feature.Type, feature.Value, feature.NameCode)
+		}
+
+		for _, zone := range provider.PlanZones(plan) {
+			w.exec("INSERT OR IGNORE INTO addon_plan_zones (addon_plan_id, zone) VALUES (?, ?)", id, zone)
+		}
+	}
+}
+
+// instance inserts an instance type with its flavors, tags and deployments
+func (w *sqliteWriter) instance(instance clevercloud.ProductInstance) {
+	id := w.exec(`INSERT INTO instance_types (type, version, name, variant_slug, deploy_type, description,
+		enabled, coming_soon, max_instances, default_flavor, build_flavor)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		instance.Type, instance.Version, instance.Name, instance.Variant.Slug, instance.Variant.DeployType,
+		instance.Description, instance.Enabled, instance.ComingSoon, instance.MaxInstances,
+		instance.DefaultFlavor.Name, instance.BuildFlavor.Name)
+
+	for _, flavor := range instance.Flavors {
+		// An unknown disk size is NULL rather than 0
+		var disk any
+		if flavor.Disk.Known {
+			disk = int64(flavor.Disk.Size)
+		}
+		w.exec(`INSERT INTO flavors (instance_type_id, name, slug, price_id, memory_bytes, disk_bytes, cpus, gpus,
+			price, available, microservice, machine_learning, nice, is_default)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			id, flavor.Name, flavor.EffectiveSlug(), flavor.PriceID, int64(flavor.MemorySize()), disk,
+			flavor.Cpus, flavor.Gpus, flavor.Price, flavor.Available, flavor.Microservice,
+			flavor.MachineLearning, flavor.Nice, flavor.Name == instance.DefaultFlavor.Name)
+	}
+
+	for _, tag := range instance.Tags {
+		w.exec("INSERT OR IGNORE INTO instance_tags (instance_type_id, tag) VALUES (?, ?)", id, tag)
+	}
+	for _, deployment := range instance.Deployments {
+		w.exec("INSERT OR IGNORE INTO instance_deployments (instance_type_id, deployment) VALUES (?, ?)", id, deployment)
+	}
+}
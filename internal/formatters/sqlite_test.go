@@ -0,0 +1,81 @@
+//go:build cgo
+
+package formatters
+
+import (
+	"bytes"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cc-plans-lister/test/fixtures"
+)
+
+func TestSQLiteFormatter(t *testing.T) {
+	assert.Contains(t, Names(), "sqlite")
+	for _, ext := range []string{".sqlite", ".sqlite3", ".db"} {
+		format, ok := ByExtension(ext)
+		require.True(t, ok, ext)
+		assert.Equal(t, "sqlite", format.Name)
+	}
+
+	formatter, err := GetFormatter("sqlite", Options{})
+	require.NoError(t, err)
+	assert.IsType(t, &SQLiteFormatter{}, formatter)
+
+	var buf bytes.Buffer
+	require.NoError(t, formatter.Format(fixtures.TestAddonProviders(), fixtures.TestProductInstances(), &buf))
+
+	path := filepath.Join(t.TempDir(), "catalog.sqlite")
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o644))
+
+	db, err := sql.Open("sqlite3", "file:"+path+"?mode=ro")
+	require.NoError(t, err)
+	defer db.Close()
+
+	count := func(table string) int {
+		var n int
+		require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM "+table).Scan(&n))
+		return n
+	}
+	assert.Equal(t, 2, count("providers"))
+	assert.Equal(t, 4, count("addon_plans"))
+	assert.Equal(t, 2, count("instance_types"))
+	assert.Equal(t, 3, count("flavors"))
+	assert.Equal(t, 4, count("instance_tags"))
+	assert.Equal(t, 3, count("instance_deployments"))
+
+	var version int
+	require.NoError(t, db.QueryRow("PRAGMA user_version").Scan(&version))
+	assert.Equal(t, SQLiteSchemaVersion, version)
+
+	// Tables are joined through foreign keys
+	var provider string
+	var price float64
+	err = db.QueryRow(`SELECT p.name, a.price FROM addon_plans a JOIN providers p ON p.id = a.provider_id
+		WHERE a.slug = 'large'`).Scan(&provider, &price)
+	require.NoError(t, err)
+	assert.Equal(t, "Redis", provider)
+	assert.Equal(t, 40.0, price)
+
+	// Cheapest flavor with at least 512 MiB across runtimes tagged "runtime"
+	var instanceType, flavor string
+	var disk sql.NullInt64
+	err = db.QueryRow(`SELECT i.type, f.name, f.disk_bytes FROM flavors f
+		JOIN instance_types i ON i.id = f.instance_type_id
+		JOIN instance_tags t ON t.instance_type_id = i.id AND t.tag = 'runtime'
+		WHERE f.memory_bytes >= 512 * 1024 * 1024
+		ORDER BY f.price, i.type DESC LIMIT 1`).Scan(&instanceType, &flavor, &disk)
+	require.NoError(t, err)
+	assert.Equal(t, "python", instanceType)
+	assert.Equal(t, "small", flavor)
+	assert.False(t, disk.Valid) // unknown disk sizes are NULL
+
+	var zones int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM addon_plan_zones WHERE zone = 'mtl'").Scan(&zones))
+	assert.Equal(t, 1, zones)
+}
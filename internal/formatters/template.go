@@ -0,0 +1,239 @@
+package formatters
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"cc-plans-lister/pkg/clevercloud"
+)
+
+// TemplateData is the data model passed to custom templates. Providers and
+// instances are in API order; use the sorting and filtering functions of
+// templateFuncs to rearrange them.
+type TemplateData struct {
+	GeneratedAt time.Time // generation time, in UTC
+	ToolVersion string    // cc-plans-lister version
+	Source      string    // API endpoint or snapshot the catalog comes from
+	Providers   []clevercloud.AddonProvider
+	Instances   []clevercloud.ProductInstance
+	Zones       []string // zones referenced by addon plans (or their provider regions), sorted
+}
+
+// templateFuncs are the functions available in custom templates and in the
+// HTML report template
+var templateFuncs = map[string]any{
+	// Sorting
+	"plans":          sortedPlans,
+	"flavors":        sortedFlavors,
+	"plansByPrice":   plansByPrice,
+	"flavorsByPrice": flavorsByPrice,
+	"sortProviders":  sortedProviders,
+	"sortInstances":  sortedInstances,
+
+	// Filtering
+	"enabled":     enabledInstances,
+	"available":   availableFlavors,
+	"plansInZone": plansInZone,
+
+	// Formatting
+	"slug":      func(flavor clevercloud.Flavor) string { return flavor.EffectiveSlug() },
+	"features":  func(plan clevercloud.AddonPlan) string { return formatPlanFeatures(plan, ", ") },
+	"details":   providerDetails,
+	"resources": formatFlavorResources,
+	"disk":      formatDisk,
+	"price":     func(price float64) string { return fmt.Sprintf("%.2f", price) },
+	"bytes":     func(size clevercloud.ByteSize) int64 { return int64(size) },
+	"join":      strings.Join,
+	"lower":     strings.ToLower,
+	"upper":     strings.ToUpper,
+	"yesNo": func(value bool) string {
+		if value {
+			return "Yes"
+		}
+		return "No"
+	},
+}
+
+// TemplateFormatter renders the catalog through a user-supplied template.
+// Templates producing HTML (report.html.tmpl, page.htm, ...) are parsed with
+// html/template so that catalog values are escaped; others use text/template.
+type TemplateFormatter struct {
+	// Path is the template file
+	Path string
+	// Source is exposed to the template as .Source
+	Source string
+}
+
+func init() {
+	Register(Format{
+		Name:        "template",
+		Extension:   "", // taken from the template file name, see TemplateExtension
+		MIMEType:    "text/plain",
+		Description: "Custom Go template given with --template",
+		New: func(opts Options) Formatter {
+			return &TemplateFormatter{Path: opts.Template, Source: opts.Source}
+		},
+	})
+}
+
+// Format renders the template for addon providers and product instances
+func (f *TemplateFormatter) Format(providers []clevercloud.AddonProvider, instances []clevercloud.ProductInstance, writer io.Writer) error {
+	tmpl, err := parseTemplateFile(f.Path)
+	if err != nil {
+		return err
+	}
+
+	data := TemplateData{
+		GeneratedAt: time.Now().UTC().Truncate(time.Second),
+		ToolVersion: ToolVersion,
+		Source:      f.Source,
+		Providers:   providers,
+		Instances:   instances,
+		Zones:       buildZoneMatrix(providers).Zones,
+	}
+
+	if err := tmpl.Execute(writer, data); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	return nil
+}
+
+// CheckTemplate parses a template file, so that errors are reported before
+// the catalog is fetched
+func CheckTemplate(path string) error {
+	_, err := parseTemplateFile(path)
+	return err
+}
+
+// TemplateExtension returns the extension of the files a template produces:
+// the extension left once .tmpl, .tpl or .gotmpl is removed from its name
+// (".md" for report.md.tmpl), or ".txt" when there is none
+func TemplateExtension(path string) string {
+	name := filepath.Base(path)
+	for _, suffix := range []string{".tmpl", ".tpl", ".gotmpl"} {
+		if trimmed := strings.TrimSuffix(name, suffix); trimmed != name {
+			name = trimmed
+			break
+		}
+	}
+
+	if ext := filepath.Ext(name); ext != "" {
+		return ext
+	}
+	return ".txt"
+}
+
+// executor is implemented by both text and HTML templates
+type executor interface {
+	Execute(writer io.Writer, data any) error
+}
+
+// parseTemplateFile parses a custom template with the template functions
+func parseTemplateFile(path string) (executor, error) {
+	if path == "" {
+		return nil, fmt.Errorf("the template format requires a template file (--template)")
+	}
+
+	name := filepath.Base(path)
+	var (
+		tmpl executor
+		err  error
+	)
+	switch strings.ToLower(TemplateExtension(path)) {
+	case ".html", ".htm":
+		tmpl, err = htmltemplate.New(name).Funcs(templateFuncs).ParseFiles(path)
+	default:
+		tmpl, err = template.New(name).Funcs(templateFuncs).ParseFiles(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	return tmpl, nil
+}
+
+// sortedProviders returns the providers sorted by name
+func sortedProviders(providers []clevercloud.AddonProvider) []clevercloud.AddonProvider {
+	sorted := make([]clevercloud.AddonProvider, len(providers))
+	copy(sorted, providers)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+// sortedInstances returns the instances sorted by name, then version
+func sortedInstances(instances []clevercloud.ProductInstance) []clevercloud.ProductInstance {
+	sorted := make([]clevercloud.ProductInstance, len(instances))
+	copy(sorted, instances)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Name != sorted[j].Name {
+			return sorted[i].Name < sorted[j].Name
+		}
+		return sorted[i].Version < sorted[j].Version
+	})
+	return sorted
+}
+
+// plansByPrice returns the provider plans sorted by price, cheapest first
+func plansByPrice(provider clevercloud.AddonProvider) []clevercloud.AddonPlan {
+	plans := sortedPlans(provider)
+	sort.SliceStable(plans, func(i, j int) bool {
+		return plans[i].Price < plans[j].Price
+	})
+	return plans
+}
+
+// flavorsByPrice returns the instance flavors sorted by price, cheapest first
+func flavorsByPrice(instance clevercloud.ProductInstance) []clevercloud.Flavor {
+	flavors := sortedFlavors(instance)
+	sort.SliceStable(flavors, func(i, j int) bool {
+		return flavors[i].Price < flavors[j].Price
+	})
+	return flavors
+}
+
+// enabledInstances returns the instances that can be deployed
+func enabledInstances(instances []clevercloud.ProductInstance) []clevercloud.ProductInstance {
+	var enabled []clevercloud.ProductInstance
+	for _, instance := range instances {
+		if instance.Enabled {
+			enabled = append(enabled, instance)
+		}
+	}
+	return enabled
+}
+
+// availableFlavors returns the flavors that can currently be selected
+func availableFlavors(flavors []clevercloud.Flavor) []clevercloud.Flavor {
+	var available []clevercloud.Flavor
+	for _, flavor := range flavors {
+		if flavor.Available {
+			available = append(available, flavor)
+		}
+	}
+	return available
+}
+
+// plansInZone returns the plans of a provider available in the given zone,
+// sorted by slug. Like the --zone filter, plans without zones fall back to the
+// provider regions, and plans without any zone information are kept since
+// their availability is unknown.
+func plansInZone(zone string, provider clevercloud.AddonProvider) []clevercloud.AddonPlan {
+	var filtered []clevercloud.AddonPlan
+	for _, plan := range sortedPlans(provider) {
+		zones := provider.PlanZones(plan)
+		if len(zones) == 0 || slices.Contains(zones, zone) {
+			filtered = append(filtered, plan)
+		}
+	}
+	return filtered
+}
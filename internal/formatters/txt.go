@@ -13,6 +13,17 @@ import (
 // TextFormatter generates plain text tabular output
 type TextFormatter struct{}
 
+func init() {
+	Register(Format{
+		Name:        "txt",
+		Aliases:     []string{"text"},
+		Extension:   ".txt",
+		MIMEType:    "text/plain",
+		Description: "Plain text report for terminals",
+		New:         func(Options) Formatter { return &TextFormatter{} },
+	})
+}
+
 // Format generates plain text tabular output for addon providers and product instances
 func (f *TextFormatter) Format(providers []clevercloud.AddonProvider, instances []clevercloud.ProductInstance, writer io.Writer) error {
 	var builder strings.Builder
@@ -57,12 +68,12 @@ func (f *TextFormatter) Format(providers []clevercloud.AddonProvider, instances
 	builder.WriteString("====================\n\n")
 
 	w = tabwriter.NewWriter(&builder, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "Provider ID\tProvider Name\tPlan ID\tPlan Name\tPlan Slug")
-	fmt.Fprintln(w, "-----------\t-------------\t-------\t---------\t---------")
+	fmt.Fprintln(w, "Provider ID\tProvider Name\tPlan ID\tPlan Name\tPlan Slug\tPrice\tFeatures")
+	fmt.Fprintln(w, "-----------\t-------------\t-------\t---------\t---------\t-----\t--------")
 
 	for _, provider := range providers {
 		if len(provider.Plans) == 0 {
-			fmt.Fprintf(w, "%s\t%s\t-\tNo plans available\t-\n", provider.ID, provider.Name)
+			fmt.Fprintf(w, "%s\t%s\t-\tNo plans available\t-\t-\t-\n", provider.ID, provider.Name)
 			continue
 		}
 
@@ -80,8 +91,12 @@ func (f *TextFormatter) Format(providers []clevercloud.AddonProvider, instances
 				providerID = provider.ID
 				providerName = provider.Name
 			}
-			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
-				providerID, providerName, plan.ID, plan.Name, plan.Slug)
+			features := formatPlanFeatures(plan, ", ")
+			if features == "" {
+				features = "-"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%.2f€/month\t%s\n",
+				providerID, providerName, plan.ID, plan.Name, plan.Slug, plan.Price, features)
 		}
 	}
 	w.Flush()
@@ -91,16 +106,12 @@ func (f *TextFormatter) Format(providers []clevercloud.AddonProvider, instances
 	builder.WriteString("============================\n\n")
 
 	w = tabwriter.NewWriter(&builder, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "Type\tName\tFlavor\tFlavor Slug\tMemory\tCPU\tPrice\tAvailable\tMicroservice\tML")
-	fmt.Fprintln(w, "----\t----\t------\t-----------\t------\t---\t-----\t---------\t------------\t--")
+	fmt.Fprintln(w, "Type\tName\tFlavor\tFlavor Slug\tMemory\tDisk\tCPU\tPrice\tAvailable\tMicroservice\tML")
+	fmt.Fprintln(w, "----\t----\t------\t-----------\t------\t----\t---\t-----\t---------\t------------\t--")
 
 	for _, instance := range instances {
-		if !instance.Enabled {
-			continue
-		}
-
 		if len(instance.Flavors) == 0 {
-			fmt.Fprintf(w, "%s\t%s\t-\t-\t-\t-\t-\t-\t-\t-\n", instance.Type, instance.Name)
+			fmt.Fprintf(w, "%s\t%s\t-\t-\t-\t-\t-\t-\t-\t-\t-\n", instance.Type, instance.Name)
 			continue
 		}
 
@@ -134,18 +145,8 @@ func (f *TextFormatter) Format(providers []clevercloud.AddonProvider, instances
 				mlStr = "Yes"
 			}
 
-			// Use flavor slug if available, otherwise use PriceID or name as fallback
-			flavorSlug := flavor.Slug
-			if flavorSlug == "" {
-				if flavor.PriceID != "" {
-					flavorSlug = flavor.PriceID
-				} else {
-					flavorSlug = flavor.Name
-				}
-			}
-
-			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%.2f€\t%s\t%s\t%s\n",
-				typeCell, nameCell, flavor.Name, flavorSlug, flavor.Memory.Formatted, flavor.Cpus, flavor.Price, availableStr, microserviceStr, mlStr)
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%d\t%.2f€\t%s\t%s\t%s\n",
+				typeCell, nameCell, flavor.Name, flavor.EffectiveSlug(), flavor.MemorySize(), formatDisk(flavor), flavor.Cpus, flavor.Price, availableStr, microserviceStr, mlStr)
 		}
 	}
 	w.Flush()
@@ -158,6 +159,17 @@ func (f *TextFormatter) Format(providers []clevercloud.AddonProvider, instances
 		builder.WriteString(fmt.Sprintf("%s (%s)\n", provider.Name, provider.ID))
 		builder.WriteString(strings.Repeat("-", len(provider.Name)+len(provider.ID)+3) + "\n")
 
+		if provider.ShortDesc != "" {
+			builder.WriteString(provider.ShortDesc + "\n")
+		}
+		if provider.LongDesc != "" {
+			builder.WriteString(provider.LongDesc + "\n")
+		}
+		for _, detail := range providerDetails(provider) {
+			builder.WriteString(fmt.Sprintf("%s: %s\n", detail.Label, detail.Value))
+		}
+		builder.WriteString("\n")
+
 		if len(provider.Plans) == 0 {
 			builder.WriteString("No plans available.\n\n")
 			continue
@@ -170,7 +182,13 @@ func (f *TextFormatter) Format(providers []clevercloud.AddonProvider, instances
 		})
 
 		for _, plan := range plans {
-			builder.WriteString(fmt.Sprintf("- %s (%s) - ID: %s\n", plan.Name, plan.Slug, plan.ID))
+			builder.WriteString(fmt.Sprintf("- %s (%s) - ID: %s - %.2f€/month\n", plan.Name, plan.Slug, plan.ID, plan.Price))
+			for _, feature := range plan.Features {
+				builder.WriteString(fmt.Sprintf("    %s: %s\n", feature.Name, feature.Value))
+			}
+			if zones := provider.PlanZones(plan); len(zones) > 0 {
+				builder.WriteString(fmt.Sprintf("    Zones: %s\n", strings.Join(zones, ", ")))
+			}
 		}
 		builder.WriteString("\n")
 	}
@@ -180,10 +198,6 @@ func (f *TextFormatter) Format(providers []clevercloud.AddonProvider, instances
 	builder.WriteString("===========================\n\n")
 
 	for _, instance := range instances {
-		if !instance.Enabled {
-			continue
-		}
-
 		title := fmt.Sprintf("%s (%s) - Version %s", instance.Name, instance.Type, instance.Version)
 		builder.WriteString(title + "\n")
 		builder.WriteString(strings.Repeat("-", len(title)) + "\n")
@@ -210,8 +224,8 @@ func (f *TextFormatter) Format(providers []clevercloud.AddonProvider, instances
 				defaultMarker = " (default)"
 			}
 
-			builder.WriteString(fmt.Sprintf("- %s%s - %s, %d CPU, %.2f€/h",
-				flavor.Name, defaultMarker, flavor.Memory.Formatted, flavor.Cpus, flavor.Price))
+			builder.WriteString(fmt.Sprintf("- %s%s - %s, %.2f€/h",
+				flavor.Name, defaultMarker, formatFlavorResources(flavor), flavor.Price))
 
 			var tags []string
 			if !flavor.Available {
@@ -232,6 +246,35 @@ func (f *TextFormatter) Format(providers []clevercloud.AddonProvider, instances
 		builder.WriteString("\n")
 	}
 
+	// Zone availability matrix
+	matrix := buildZoneMatrix(providers)
+	if len(matrix.Zones) > 0 {
+		builder.WriteString("ZONE AVAILABILITY\n")
+		builder.WriteString("=================\n\n")
+
+		w = tabwriter.NewWriter(&builder, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "Addon plan\t"+strings.Join(matrix.Zones, "\t"))
+		separators := make([]string, len(matrix.Zones))
+		for i, zone := range matrix.Zones {
+			separators[i] = strings.Repeat("-", len(zone))
+		}
+		fmt.Fprintln(w, "----------\t"+strings.Join(separators, "\t"))
+
+		for _, row := range matrix.Rows {
+			cells := make([]string, len(matrix.Zones))
+			for i, zone := range matrix.Zones {
+				if row.Zones[zone] {
+					cells[i] = "✓"
+				}
+			}
+			fmt.Fprintf(w, "%s\t%s\n", row.Item, strings.Join(cells, "\t"))
+		}
+		w.Flush()
+		if note := matrix.UnknownNote(); note != "" {
+			builder.WriteString("\n" + note + ".\n")
+		}
+	}
+
 	_, err := writer.Write([]byte(builder.String()))
 	return err
 }
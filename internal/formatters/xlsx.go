@@ -0,0 +1,309 @@
+package formatters
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+
+	"cc-plans-lister/pkg/clevercloud"
+)
+
+// Number formats of the typed worksheet cells. Sizes are whole numbers of
+// MiB or GiB most of the time, which "General" shows without the trailing
+// decimal separator of a "#,##0.##" format, while keeping fractions.
+const (
+	xlsxIntegerFormat = "0"
+	xlsxSizeFormat    = "General"
+	xlsxMonthlyFormat = "#,##0.00"
+	xlsxHourlyFormat  = "#,##0.00##"
+)
+
+// XLSXFormatter generates an Excel workbook with one sheet per section.
+// Prices, sizes and counts are numeric cells and flags are booleans, so the
+// sheets can be sorted, filtered and used in formulas without any cleanup.
+type XLSXFormatter struct{}
+
+func init() {
+	Register(Format{
+		Name:        "xlsx",
+		Aliases:     []string{"excel"},
+		Extension:   ".xlsx",
+		MIMEType:    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		Description: "Excel workbook with one sheet per section",
+		New:         func(Options) Formatter { return &XLSXFormatter{} },
+	})
+}
+
+// xlsxColumn is a column of a worksheet
+type xlsxColumn struct {
+	Header string
+	Width  float64
+	Format string // number format of the column cells, empty for text
+}
+
+// xlsxWorkbook writes worksheets with a styled, frozen header row and an
+// autofilter on every column
+type xlsxWorkbook struct {
+	file   *excelize.File
+	header int
+	styles map[string]int // number format styles, by format
+	sheets int
+}
+
+// Format generates an XLSX workbook for addon providers and product instances.
+// With --include-disabled, the autofilter of the Enabled column hides the
+// disabled instance types again in a click.
+func (f *XLSXFormatter) Format(providers []clevercloud.AddonProvider, instances []clevercloud.ProductInstance, writer io.Writer) error {
+	file := excelize.NewFile()
+	defer file.Close()
+
+	workbook, err := newXLSXWorkbook(file)
+	if err != nil {
+		return err
+	}
+
+	// Addon Summary
+	var rows [][]any
+	for _, provider := range providers {
+		rows = append(rows, []any{provider.ID, provider.Name, strings.ToLower(provider.Status),
+			len(provider.Plans), provider.Website, strings.Join(provider.Regions, ", ")})
+	}
+	err = workbook.sheet("Addon Summary", []xlsxColumn{
+		{Header: "Provider ID", Width: 24},
+		{Header: "Name", Width: 30},
+		{Header: "Status", Width: 10},
+		{Header: "Plans", Width: 8, Format: xlsxIntegerFormat},
+		{Header: "Website", Width: 36},
+		{Header: "Regions", Width: 24},
+	}, rows)
+	if err != nil {
+		return err
+	}
+
+	// Addon Plans
+	rows = nil
+	for _, provider := range providers {
+		for _, plan := range sortedPlans(provider) {
+			rows = append(rows, []any{provider.ID, provider.Name, plan.ID, plan.Name, plan.Slug,
+				plan.Price, formatPlanFeatures(plan, "; "), strings.Join(provider.PlanZones(plan), ", ")})
+		}
+	}
+	err = workbook.sheet("Addon Plans", []xlsxColumn{
+		{Header: "Provider ID", Width: 24},
+		{Header: "Provider Name", Width: 30},
+		{Header: "Plan ID", Width: 30},
+		{Header: "Plan Name", Width: 30},
+		{Header: "Plan Slug", Width: 16},
+		{Header: "Price (€/month)", Width: 16, Format: xlsxMonthlyFormat},
+		{Header: "Features", Width: 60},
+		{Header: "Zones", Width: 24},
+	}, rows)
+	if err != nil {
+		return err
+	}
+
+	// Application Summary
+	rows = nil
+	for _, instance := range instances {
+		rows = append(rows, []any{instance.Type, instance.Name, instance.Version, instance.Enabled,
+			len(instance.Flavors), instance.DefaultFlavor.Name, instance.MaxInstances,
+			strings.Join(instance.Tags, ", "), strings.Join(instance.Deployments, ", ")})
+	}
+	err = workbook.sheet("Application Summary", []xlsxColumn{
+		{Header: "Type", Width: 16},
+		{Header: "Name", Width: 24},
+		{Header: "Version", Width: 12},
+		{Header: "Enabled", Width: 10},
+		{Header: "Flavors", Width: 10, Format: xlsxIntegerFormat},
+		{Header: "Default Flavor", Width: 16},
+		{Header: "Max Instances", Width: 14, Format: xlsxIntegerFormat},
+		{Header: "Tags", Width: 30},
+		{Header: "Deployments", Width: 24},
+	}, rows)
+	if err != nil {
+		return err
+	}
+
+	// Flavors, with sizes in MiB and GiB so that they read naturally while
+	// staying numeric; an unknown disk size is left empty rather than set to 0
+	rows = nil
+	for _, instance := range instances {
+		for _, flavor := range sortedFlavors(instance) {
+			var disk any
+			if flavor.Disk.Known {
+				disk = float64(flavor.Disk.Size) / float64(clevercloud.GiB)
+			}
+			rows = append(rows, []any{instance.Type, instance.Name, instance.Version, instance.Enabled,
+				flavor.Name, flavor.EffectiveSlug(), float64(flavor.MemorySize()) / float64(clevercloud.MiB), disk,
+				flavor.Cpus, flavor.Gpus, flavor.Price, flavor.Available, flavor.Microservice,
+				flavor.MachineLearning, flavor.Name == instance.DefaultFlavor.Name})
+		}
+	}
+	err = workbook.sheet("Flavors", []xlsxColumn{
+		{Header: "Type", Width: 16},
+		{Header: "Name", Width: 24},
+		{Header: "Version", Width: 12},
+		{Header: "Enabled", Width: 10},
+		{Header: "Flavor", Width: 12},
+		{Header: "Flavor Slug", Width: 20},
+		{Header: "Memory (MiB)", Width: 14, Format: xlsxSizeFormat},
+		{Header: "Disk (GiB)", Width: 12, Format: xlsxSizeFormat},
+		{Header: "CPUs", Width: 8, Format: xlsxIntegerFormat},
+		{Header: "GPUs", Width: 8, Format: xlsxIntegerFormat},
+		{Header: "Price (€/hour)", Width: 14, Format: xlsxHourlyFormat},
+		{Header: "Available", Width: 11},
+		{Header: "Microservice", Width: 13},
+		{Header: "Machine Learning", Width: 17},
+		{Header: "Default", Width: 10},
+	}, rows)
+	if err != nil {
+		return err
+	}
+
+	// Zone availability matrix
+	matrix := buildZoneMatrix(providers)
+	if len(matrix.Zones) > 0 {
+		columns := []xlsxColumn{{Header: "Addon Plan", Width: 30}}
+		for _, zone := range matrix.Zones {
+			columns = append(columns, xlsxColumn{Header: zone, Width: 9})
+		}
+
+		rows = nil
+		for _, row := range matrix.Rows {
+			cells := []any{row.Item}
+			for _, zone := range matrix.Zones {
+				cells = append(cells, row.Zones[zone])
+			}
+			rows = append(rows, cells)
+		}
+		// Unknown availability is left empty rather than reported as false
+		for _, row := range matrix.Unknown {
+			rows = append(rows, []any{row.Item})
+		}
+
+		if err := workbook.sheet("Zone Availability", columns, rows); err != nil {
+			return err
+		}
+	}
+
+	err = file.SetDocProps(&excelize.DocProperties{
+		Title:   "Complete Clever Cloud Services Overview",
+		Creator: "cc-plans-lister " + ToolVersion,
+	})
+	if err != nil {
+		return err
+	}
+
+	file.SetActiveSheet(0)
+	return file.Write(writer)
+}
+
+// newXLSXWorkbook prepares the header style of the workbook sheets
+func newXLSXWorkbook(file *excelize.File) (*xlsxWorkbook, error) {
+	header, err := file.NewStyle(&excelize.Style{
+		Font:   &excelize.Font{Bold: true},
+		Fill:   excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"DDE3EE"}},
+		Border: []excelize.Border{{Type: "bottom", Color: "8C96A8", Style: 1}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create XLSX style: %w", err)
+	}
+
+	return &xlsxWorkbook{file: file, header: header, styles: make(map[string]int)}, nil
+}
+
+// sheet adds a worksheet holding a table. Nil cells are left empty.
+func (w *xlsxWorkbook) sheet(name string, columns []xlsxColumn, rows [][]any) error {
+	// The first sheet replaces the default one of new workbooks
+	if w.sheets == 0 {
+		if err := w.file.SetSheetName(w.file.GetSheetName(0), name); err != nil {
+			return err
+		}
+	} else if _, err := w.file.NewSheet(name); err != nil {
+		return err
+	}
+	w.sheets++
+
+	for i, column := range columns {
+		if err := w.file.SetCellValue(name, xlsxCell(i, 0), column.Header); err != nil {
+			return err
+		}
+		col := xlsxColumnName(i)
+		if err := w.file.SetColWidth(name, col, col, column.Width); err != nil {
+			return err
+		}
+	}
+
+	for r, row := range rows {
+		for i, value := range row {
+			if value == nil {
+				continue
+			}
+			if err := w.file.SetCellValue(name, xlsxCell(i, r+1), value); err != nil {
+				return err
+			}
+		}
+	}
+
+	last := xlsxCell(len(columns)-1, len(rows))
+	if err := w.file.SetCellStyle(name, "A1", xlsxCell(len(columns)-1, 0), w.header); err != nil {
+		return err
+	}
+	if len(rows) > 0 {
+		for i, column := range columns {
+			if column.Format == "" {
+				continue
+			}
+			style, err := w.numberStyle(column.Format)
+			if err != nil {
+				return err
+			}
+			if err := w.file.SetCellStyle(name, xlsxCell(i, 1), xlsxCell(i, len(rows)), style); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Keep the header row visible while scrolling
+	err := w.file.SetPanes(name, &excelize.Panes{
+		Freeze:      true,
+		YSplit:      1,
+		TopLeftCell: "A2",
+		ActivePane:  "bottomLeft",
+	})
+	if err != nil {
+		return err
+	}
+
+	return w.file.AutoFilter(name, "A1:"+last, nil)
+}
+
+// numberStyle returns the style of cells with the given number format
+func (w *xlsxWorkbook) numberStyle(format string) (int, error) {
+	if style, ok := w.styles[format]; ok {
+		return style, nil
+	}
+
+	style, err := w.file.NewStyle(&excelize.Style{CustomNumFmt: &format})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create XLSX style: %w", err)
+	}
+	w.styles[format] = style
+	return style, nil
+}
+
+// xlsxCell returns the name of the cell at the given zero-based column and row
+func xlsxCell(column, row int) string {
+	return fmt.Sprintf("%s%d", xlsxColumnName(column), row+1)
+}
+
+// xlsxColumnName returns the name of the zero-based column ("A", ..., "AA", ...)
+func xlsxColumnName(column int) string {
+	name := ""
+	for column++; column > 0; column = (column - 1) / 26 {
+		name = string(rune('A'+(column-1)%26)) + name
+	}
+	return name
+}
@@ -0,0 +1,79 @@
+package formatters
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+
+	"cc-plans-lister/pkg/clevercloud"
+)
+
+// YAMLFormatter generates a YAML document with the same layout and field
+// names as the JSON document, so both can be consumed by the same tools
+type YAMLFormatter struct{}
+
+func init() {
+	Register(Format{
+		Name:        "yaml",
+		Aliases:     []string{"yml"},
+		Extension:   ".yaml",
+		MIMEType:    "application/yaml",
+		Description: "YAML document for GitOps repositories and configuration management",
+		New:         func(Options) Formatter { return &YAMLFormatter{} },
+	})
+}
+
+// Format generates a YAML document for addon providers and product instances.
+// The document is first encoded as JSON and then converted node by node, so
+// keys keep the order of the JSON document and diffs between runs stay small.
+func (f *YAMLFormatter) Format(providers []clevercloud.AddonProvider, instances []clevercloud.ProductInstance, writer io.Writer) error {
+	data, err := json.Marshal(newJSONDocument(providers, instances))
+	if err != nil {
+		return fmt.Errorf("failed to encode catalog: %w", err)
+	}
+
+	// JSON is valid YAML: decoding it into a node keeps the key order
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return fmt.Errorf("failed to convert catalog to YAML: %w", err)
+	}
+	blockStyle(&node)
+
+	encoder := yaml.NewEncoder(writer)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// blockStyle drops the flow style and quoting the nodes got from their JSON
+// source, so that the document is written in the usual block style. The
+// encoder quotes strings that YAML 1.2 would read as another type (e.g. "1.0"
+// or "true"); strings that YAML 1.1 parsers would read as booleans or
+// sexagesimal numbers (e.g. "yes", "off" or "1:30") keep their quotes too.
+func blockStyle(node *yaml.Node) {
+	if node.Kind != yaml.ScalarNode || node.Tag != "!!str" || !yaml11Ambiguous(node.Value) {
+		node.Style = 0
+	}
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
+
+// yaml11Sexagesimal matches the base 60 numbers of YAML 1.1, e.g. "1:30"
+var yaml11Sexagesimal = regexp.MustCompile(`^[-+]?[0-9][0-9_]*(?::[0-5]?[0-9])+(?:\.[0-9_]*)?$`)
+
+// yaml11Ambiguous reports whether a plain string would be read as a boolean
+// or a number by a YAML 1.1 parser
+func yaml11Ambiguous(value string) bool {
+	switch value {
+	case "y", "Y", "yes", "Yes", "YES", "n", "N", "no", "No", "NO",
+		"on", "On", "ON", "off", "Off", "OFF":
+		return true
+	}
+	return yaml11Sexagesimal.MatchString(value)
+}
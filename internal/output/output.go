@@ -0,0 +1,118 @@
+package output
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"cc-plans-lister/internal/formatters"
+)
+
+// DefaultFilenameTemplate names the files written to an output directory
+const DefaultFilenameTemplate = "clever-cloud-services.{{.Ext}}"
+
+// Target is a report to generate: a format and the file it is written to.
+// An empty Path means standard output.
+type Target struct {
+	Format formatters.Format
+	Path   string
+}
+
+// Dir returns the directory used for the target by formatters that write
+// several files: its path without the extension, so that the filename
+// template names these outputs too (e.g. reports/2024-01-31/services/ for
+// reports/2024-01-31/services.csv)
+func (t Target) Dir() string {
+	return strings.TrimSuffix(t.Path, filepath.Ext(t.Path))
+}
+
+// FilenameData holds the fields available in filename templates
+type FilenameData struct {
+	Format    string // format name, e.g. "markdown"
+	Ext       string // file extension without the dot, e.g. "md"
+	Date      string // generation date, e.g. "2024-01-31"
+	Timestamp string // generation time, e.g. "20240131-093000"
+	Org       string // organisation ID given with --org, if any
+	Zone      string // zone given with --zone, if any
+}
+
+// NewFilenameData returns the template fields shared by every format of a run
+func NewFilenameData(now time.Time, org, zone string) FilenameData {
+	return FilenameData{
+		Date:      now.Format("2006-01-02"),
+		Timestamp: now.Format("20060102-150405"),
+		Org:       org,
+		Zone:      zone,
+	}
+}
+
+// ParseFormats parses a comma-separated list of format names or aliases.
+// Duplicates (including an alias of an already listed format) are dropped.
+func ParseFormats(list string) ([]formatters.Format, error) {
+	var formats []formatters.Format
+	seen := make(map[string]bool)
+
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		format, err := formatters.ParseFormat(name)
+		if err != nil {
+			return nil, err
+		}
+
+		if seen[format.Name] {
+			continue
+		}
+		seen[format.Name] = true
+		formats = append(formats, format)
+	}
+
+	if len(formats) == 0 {
+		return nil, fmt.Errorf("no output format given (supported: %s)", strings.Join(formatters.Names(), ", "))
+	}
+
+	return formats, nil
+}
+
+// Targets returns one target per format in dir, named after the filename
+// template. It fails when the template does not yield a distinct file name
+// for every format (e.g. when it uses neither {{.Ext}} nor {{.Format}}).
+func Targets(dir, filenameTemplate string, formats []formatters.Format, data FilenameData) ([]Target, error) {
+	tmpl, err := template.New("filename").Option("missingkey=error").Parse(filenameTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filename template: %w", err)
+	}
+
+	targets := make([]Target, 0, len(formats))
+	seen := make(map[string]string)
+
+	for _, format := range formats {
+		data.Format = format.Name
+		data.Ext = strings.TrimPrefix(format.Extension, ".")
+
+		var name strings.Builder
+		if err := tmpl.Execute(&name, data); err != nil {
+			return nil, fmt.Errorf("invalid filename template: %w", err)
+		}
+
+		filename := strings.TrimSpace(name.String())
+		if filename == "" {
+			return nil, fmt.Errorf("filename template %q yields an empty name for %s", filenameTemplate, format.Name)
+		}
+
+		path := filepath.Join(dir, filename)
+		if other, ok := seen[path]; ok {
+			return nil, fmt.Errorf("filename template %q yields %s for both %s and %s", filenameTemplate, filename, other, format.Name)
+		}
+		seen[path] = format.Name
+
+		targets = append(targets, Target{Format: format, Path: path})
+	}
+
+	return targets, nil
+}
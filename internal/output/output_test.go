@@ -0,0 +1,61 @@
+package output
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFormats(t *testing.T) {
+	formats, err := ParseFormats("markdown, csv,md,pdf")
+	require.NoError(t, err)
+
+	var names []string
+	for _, format := range formats {
+		names = append(names, format.Name)
+	}
+	assert.Equal(t, []string{"markdown", "csv", "pdf"}, names)
+
+	_, err = ParseFormats("markdown,xml")
+	assert.ErrorContains(t, err, "unsupported output format: xml")
+
+	_, err = ParseFormats(" , ")
+	assert.Error(t, err)
+}
+
+func TestTargets(t *testing.T) {
+	formats, err := ParseFormats("markdown,csv,pdf")
+	require.NoError(t, err)
+
+	data := NewFilenameData(time.Date(2024, 1, 31, 9, 30, 0, 0, time.UTC), "", "par")
+
+	targets, err := Targets("reports", DefaultFilenameTemplate, formats, data)
+	require.NoError(t, err)
+	require.Len(t, targets, 3)
+	assert.Equal(t, filepath.Join("reports", "clever-cloud-services.md"), targets[0].Path)
+	assert.Equal(t, filepath.Join("reports", "clever-cloud-services.csv"), targets[1].Path)
+	assert.Equal(t, "pdf", targets[2].Format.Name)
+
+	targets, err = Targets("reports", "{{.Date}}/services{{if .Zone}}-{{.Zone}}{{end}}.{{.Ext}}", formats, data)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("reports", "2024-01-31", "services-par.md"), targets[0].Path)
+	assert.Equal(t, filepath.Join("reports", "2024-01-31", "services-par"), targets[1].Dir())
+
+	// Without an extension, the directory is the file name itself
+	targets, err = Targets("reports", "{{.Date}}-{{.Format}}", formats, data)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("reports", "2024-01-31-csv"), targets[1].Dir())
+
+	// Every format must get its own file
+	_, err = Targets("reports", "services-{{.Date}}", formats, data)
+	assert.ErrorContains(t, err, "for both markdown and csv")
+
+	_, err = Targets("reports", "{{.Unknown}}", formats, data)
+	assert.ErrorContains(t, err, "invalid filename template")
+
+	_, err = Targets("reports", "{{.Ext", formats, data)
+	assert.ErrorContains(t, err, "invalid filename template")
+}
@@ -0,0 +1,98 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"cc-plans-lister/pkg/clevercloud"
+)
+
+// FormatVersion is the version of the snapshot file layout
+const FormatVersion = 1
+
+// Snapshot holds a catalog as fetched from the Clever Cloud API so that it can
+// be rendered later without network access or credentials. The catalog is
+// stored in the normalized model of the tool rather than as raw API payloads.
+type Snapshot struct {
+	Version   int                           `json:"snapshot_version"`
+	CreatedAt time.Time                     `json:"created_at"`
+	Providers []clevercloud.AddonProvider   `json:"providers"`
+	Instances []clevercloud.ProductInstance `json:"instances"`
+}
+
+// New creates a snapshot of the given catalog stamped with the current time
+func New(providers []clevercloud.AddonProvider, instances []clevercloud.ProductInstance) *Snapshot {
+	return &Snapshot{
+		Version:   FormatVersion,
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+		Providers: providers,
+		Instances: instances,
+	}
+}
+
+// Save writes the snapshot to the given file as JSON
+func Save(path string, snap *Snapshot) error {
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// jsonSchemaVersions lists the versions of JSON format documents that can be
+// loaded. Sizes written with version 1 are in the units of the API, which
+// flavors and disks are normalized from when decoded.
+var jsonSchemaVersions = []string{"1", clevercloud.JSONSchemaVersion}
+
+// Load reads a snapshot from the given file. Documents produced by the json
+// output format share the providers/instances layout and are accepted too.
+// Their generated_at time is used as the snapshot creation time. Other JSON
+// documents are rejected: the file must carry the version marker
+// of either layout and at least one of the providers and instances sections.
+func Load(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	var header struct {
+		Version       int             `json:"snapshot_version"`
+		SchemaVersion string          `json:"schema_version"`
+		GeneratedAt   time.Time       `json:"generated_at"`
+		Providers     json.RawMessage `json:"providers"`
+		Instances     json.RawMessage `json:"instances"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot %s: %w", path, err)
+	}
+
+	switch {
+	case header.Version > FormatVersion:
+		return nil, fmt.Errorf("snapshot %s has unsupported version %d (max supported: %d)", path, header.Version, FormatVersion)
+	case header.Version == 0 && header.SchemaVersion == "":
+		return nil, fmt.Errorf("%s is not a snapshot: snapshot_version or schema_version is missing", path)
+	case header.Version == 0 && !slices.Contains(jsonSchemaVersions, header.SchemaVersion):
+		return nil, fmt.Errorf("%s has unsupported JSON schema version %q (supported: %s)", path, header.SchemaVersion, strings.Join(jsonSchemaVersions, ", "))
+	case header.Providers == nil && header.Instances == nil:
+		return nil, fmt.Errorf("%s is not a snapshot: it has neither providers nor instances", path)
+	}
+
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot %s: %w", path, err)
+	}
+	if header.Version == 0 {
+		snap.CreatedAt = header.GeneratedAt
+	}
+
+	return &snap, nil
+}
@@ -0,0 +1,108 @@
+package snapshot
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"cc-plans-lister/internal/formatters"
+	"cc-plans-lister/pkg/clevercloud"
+	"cc-plans-lister/test/fixtures"
+)
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "catalog.json")
+
+	snap := New(fixtures.TestAddonProviders(), fixtures.TestProductInstances())
+	require.NoError(t, Save(path, snap))
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, FormatVersion, loaded.Version)
+	assert.True(t, snap.CreatedAt.Equal(loaded.CreatedAt))
+	assert.Equal(t, snap.Providers, loaded.Providers)
+	assert.Equal(t, snap.Instances, loaded.Instances)
+}
+
+func TestLoadJSONFormatterOutput(t *testing.T) {
+	var buf bytes.Buffer
+	err := (&formatters.JSONFormatter{}).Format(fixtures.TestAddonProviders(), fixtures.TestProductInstances(), &buf)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "services.json")
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o644))
+
+	var doc struct {
+		GeneratedAt time.Time `json:"generated_at"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	assert.False(t, loaded.CreatedAt.IsZero())
+	assert.True(t, doc.GeneratedAt.Equal(loaded.CreatedAt))
+	assert.Equal(t, fixtures.TestAddonProviders(), loaded.Providers)
+	assert.Equal(t, fixtures.TestProductInstances(), loaded.Instances)
+}
+
+func TestLoadJSONSchemaVersion1(t *testing.T) {
+	// Version 1 documents kept the memory and disk sizes of the API
+	path := filepath.Join(t.TempDir(), "services.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{
+		"schema_version": "1",
+		"providers": [],
+		"instances": [{"type": "node", "flavors": [{
+			"name": "S",
+			"memory": {"unit": "MB", "value": 1024, "formatted": "1 GB"},
+			"disk": 20
+		}]}]
+	}`), 0o644))
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	require.Len(t, loaded.Instances, 1)
+	flavor := loaded.Instances[0].Flavors[0]
+	assert.Equal(t, clevercloud.GiB, flavor.MemorySize())
+	assert.Equal(t, int64(clevercloud.GiB), flavor.Memory.Value)
+	assert.True(t, flavor.Disk.Known)
+}
+
+func TestLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := Load(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+
+	invalid := filepath.Join(dir, "invalid.json")
+	require.NoError(t, os.WriteFile(invalid, []byte("{not json"), 0o644))
+	_, err = Load(invalid)
+	assert.Error(t, err)
+
+	tests := []struct {
+		name    string
+		content string
+		err     string
+	}{
+		{"future", `{"snapshot_version": 99, "providers": []}`, "unsupported version"},
+		{"empty object", `{}`, "snapshot_version or schema_version is missing"},
+		{"other document", `{"name": "app", "providers": []}`, "snapshot_version or schema_version is missing"},
+		{"future schema", `{"schema_version": "3", "providers": []}`, `unsupported JSON schema version "3"`},
+		{"no sections", `{"snapshot_version": 1, "created_at": "2024-01-01T00:00:00Z"}`, "neither providers nor instances"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.name+".json")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o644))
+			_, err := Load(path)
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package clevercloud
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ByteSize is a size in bytes
+type ByteSize int64
+
+// Size units. The API uses binary multiples, including for the SI-looking
+// unit names (a "512 MB" flavor has 512 MiB of memory).
+const (
+	Byte ByteSize = 1
+	KiB           = 1024 * Byte
+	MiB           = 1024 * KiB
+	GiB           = 1024 * MiB
+	TiB           = 1024 * GiB
+)
+
+// sizeUnits lists the unit names accepted in API size objects
+var sizeUnits = map[string]ByteSize{
+	"":    Byte,
+	"B":   Byte,
+	"KB":  KiB,
+	"KIB": KiB,
+	"MB":  MiB,
+	"MIB": MiB,
+	"GB":  GiB,
+	"GIB": GiB,
+	"TB":  TiB,
+	"TIB": TiB,
+}
+
+// String formats the size with the largest binary unit, e.g. "512 MiB" or "1.5 GiB"
+func (s ByteSize) String() string {
+	units := []struct {
+		size ByteSize
+		name string
+	}{{TiB, "TiB"}, {GiB, "GiB"}, {MiB, "MiB"}, {KiB, "KiB"}}
+
+	for _, unit := range units {
+		if s >= unit.size {
+			value := strconv.FormatFloat(float64(s)/float64(unit.size), 'f', 1, 64)
+			return strings.TrimSuffix(value, ".0") + " " + unit.name
+		}
+	}
+	return strconv.FormatInt(int64(s), 10) + " B"
+}
+
+// ParseByteSize parses a size such as "512MiB", "4 GB", "1.5G" or "1024".
+// Units are binary multiples, as in the API, and default to bytes.
+func ParseByteSize(s string) (ByteSize, error) {
+	s = strings.TrimSpace(s)
+	number := strings.TrimRightFunc(s, func(r rune) bool {
+		return (r < '0' || r > '9') && r != '.'
+	})
+	unit := strings.ToUpper(strings.TrimSpace(s[len(number):]))
+	if len(unit) == 1 && unit != "B" {
+		unit += "B" // "4G" is "4GB"
+	}
+
+	value, err := strconv.ParseFloat(number, 64)
+	multiplier, ok := sizeUnits[unit]
+	if err != nil || !ok || value < 0 {
+		return 0, fmt.Errorf("invalid size %q: expected a number with an optional unit (B, KiB, MiB, GiB, TiB)", s)
+	}
+	return ByteSize(value * float64(multiplier)), nil
+}
+
+// sizeOf converts a value expressed in the given API unit to bytes
+func sizeOf(value int64, unit string) (ByteSize, bool) {
+	multiplier, ok := sizeUnits[strings.ToUpper(strings.TrimSpace(unit))]
+	if !ok {
+		return 0, false
+	}
+	return ByteSize(value) * multiplier, true
+}
+
+// Disk is the disk size of a flavor. The API sends it as null when the flavor
+// has no dedicated disk, as a number of MiB, or as a size object shaped like
+// Memory. It is always encoded back as a size object in bytes (or null), so
+// snapshots and JSON exports decode to the same value.
+type Disk struct {
+	Size  ByteSize
+	Known bool // false when the API does not specify a disk size
+}
+
+// String returns the formatted size, or an empty string when it is unknown
+func (d Disk) String() string {
+	if !d.Known {
+		return ""
+	}
+	return d.Size.String()
+}
+
+// UnmarshalJSON decodes a disk size sent as null, a number of MiB or a size object
+func (d *Disk) UnmarshalJSON(data []byte) error {
+	*d = Disk{}
+
+	data = bytes.TrimSpace(data)
+	switch {
+	case bytes.Equal(data, []byte("null")):
+		return nil
+
+	case len(data) > 0 && data[0] == '{':
+		var size Memory
+		if err := json.Unmarshal(data, &size); err != nil {
+			return fmt.Errorf("invalid disk size %s: %w", data, err)
+		}
+		value, ok := sizeOf(size.Value, size.Unit)
+		if !ok {
+			return fmt.Errorf("invalid disk size %s: unknown unit %q", data, size.Unit)
+		}
+		*d = Disk{Size: value, Known: true}
+		return nil
+
+	default:
+		var mebibytes float64
+		if err := json.Unmarshal(data, &mebibytes); err != nil {
+			return fmt.Errorf("invalid disk size %s: expected null, a number or an object", data)
+		}
+		*d = Disk{Size: ByteSize(mebibytes * float64(MiB)), Known: true}
+		return nil
+	}
+}
+
+// MarshalJSON encodes the disk size as a size object in bytes, or null when unknown
+func (d Disk) MarshalJSON() ([]byte, error) {
+	if !d.Known {
+		return []byte("null"), nil
+	}
+	return json.Marshal(newMemory(d.Size))
+}
+
+// newMemory returns the size object of the given size, in bytes
+func newMemory(size ByteSize) Memory {
+	return Memory{Unit: "B", Value: int64(size), Formatted: size.String()}
+}
+
+// MemorySize returns the flavor memory in bytes. The memory object is the
+// reference; Mem (in MiB) is used when the object is missing or its unit is
+// unknown.
+func (f Flavor) MemorySize() ByteSize {
+	if f.Memory.Value > 0 {
+		if size, ok := sizeOf(f.Memory.Value, f.Memory.Unit); ok {
+			return size
+		}
+	}
+	return ByteSize(f.Mem) * MiB
+}
+
+// UnmarshalJSON decodes a flavor and normalizes its memory so that Mem, Memory
+// and MemorySize agree, with Memory expressed in bytes. Flavors without any
+// memory information (such as an unset default flavor) are left as is.
+func (f *Flavor) UnmarshalJSON(data []byte) error {
+	type rawFlavor Flavor
+	var raw rawFlavor
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*f = Flavor(raw)
+	if size := f.MemorySize(); size > 0 {
+		f.Mem = int(size / MiB)
+		f.Memory = newMemory(size)
+	}
+	return nil
+}
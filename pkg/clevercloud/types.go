@@ -1,17 +1,54 @@
 package clevercloud
 
+// JSONSchemaVersion is the version of the JSON document layout produced by
+// the json and yaml output formats, and read back as a snapshot. It is bumped
+// whenever a field is renamed, removed or changes meaning; adding fields does
+// not change it. Version 2 normalized flavor sizes: memory.value is in bytes
+// and disk is a size object (or null) instead of the raw API value.
+const JSONSchemaVersion = "2"
+
 // AddonProvider represents an addon provider with its plans
 type AddonProvider struct {
-	ID    string      `json:"id"`
-	Name  string      `json:"name"`
-	Plans []AddonPlan `json:"plans"`
+	ID           string      `json:"id"`
+	Name         string      `json:"name"`
+	ShortDesc    string      `json:"shortDesc"`
+	LongDesc     string      `json:"longDesc"`
+	Website      string      `json:"website"`
+	SupportEmail string      `json:"supportEmail"`
+	Status       string      `json:"status"` // e.g. ALPHA, BETA or RELEASE
+	Regions      []string    `json:"regions"`
+	CanUpgrade   bool        `json:"canUpgrade"` // plan can be changed after creation
+	LogoURL      string      `json:"logoUrl"`
+	Plans        []AddonPlan `json:"plans"`
+}
+
+// PlanZones returns the zones a plan of the provider is available in: the
+// zones of the plan, or the regions of the provider when the plan lists none.
+// An empty result means that the availability of the plan is unknown.
+func (p AddonProvider) PlanZones(plan AddonPlan) []string {
+	if len(plan.Zones) > 0 {
+		return plan.Zones
+	}
+	return p.Regions
 }
 
 // AddonPlan represents a specific plan for an addon
 type AddonPlan struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
-	Slug string `json:"slug"`
+	ID       string         `json:"id"`
+	Name     string         `json:"name"`
+	Slug     string         `json:"slug"`
+	Price    float64        `json:"price"` // monthly price in euros
+	PriceID  string         `json:"price_id"`
+	Features []AddonFeature `json:"features"`
+	Zones    []string       `json:"zones"`
+}
+
+// AddonFeature represents a characteristic of an addon plan (memory, disk, connection limit, backups, ...)
+type AddonFeature struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Value    string `json:"value"`
+	NameCode string `json:"name_code"`
 }
 
 // ProductInstance represents an application type with its flavors (plans)
@@ -31,6 +68,17 @@ type ProductInstance struct {
 	BuildFlavor   Flavor   `json:"buildFlavor"`
 }
 
+// Zone represents a Clever Cloud deployment zone (region)
+type Zone struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	DisplayName string   `json:"displayName"`
+	City        string   `json:"city"`
+	Country     string   `json:"country"`
+	CountryCode string   `json:"countryCode"`
+	Tags        []string `json:"tags"`
+}
+
 // Variant represents application variant information
 type Variant struct {
 	ID         string `json:"id"`
@@ -40,21 +88,22 @@ type Variant struct {
 	Logo       string `json:"logo"`
 }
 
-// Memory represents memory configuration
+// Memory represents memory configuration. Decoded flavors always carry it in
+// bytes (Unit "B"); see Flavor.MemorySize.
 type Memory struct {
 	Unit      string `json:"unit"`
-	Value     int    `json:"value"`
+	Value     int64  `json:"value"` // int64 so that byte counts fit on 32-bit platforms
 	Formatted string `json:"formatted"`
 }
 
 // Flavor represents a specific flavor/plan for an application type
 type Flavor struct {
 	Name            string  `json:"name"`
-	Slug            string  `json:"slug"`            // Add slug field to match addon plans
+	Slug            string  `json:"slug"` // Add slug field to match addon plans
 	Mem             int     `json:"mem"`
 	Cpus            int     `json:"cpus"`
 	Gpus            int     `json:"gpus"`
-	Disk            any     `json:"disk"`
+	Disk            Disk    `json:"disk"`
 	Price           float64 `json:"price"`
 	Available       bool    `json:"available"`
 	Microservice    bool    `json:"microservice"`
@@ -63,3 +112,15 @@ type Flavor struct {
 	PriceID         string  `json:"price_id"`
 	Memory          Memory  `json:"memory"`
 }
+
+// EffectiveSlug returns the flavor slug, falling back to the price ID and then
+// to the name when the API leaves the slug empty
+func (f Flavor) EffectiveSlug() string {
+	if f.Slug != "" {
+		return f.Slug
+	}
+	if f.PriceID != "" {
+		return f.PriceID
+	}
+	return f.Name
+}
@@ -0,0 +1,121 @@
+package clevercloud
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestByteSizeString(t *testing.T) {
+	tests := []struct {
+		size     ByteSize
+		expected string
+	}{
+		{0, "0 B"},
+		{512, "512 B"},
+		{512 * MiB, "512 MiB"},
+		{GiB, "1 GiB"},
+		{3 * GiB / 2, "1.5 GiB"},
+		{2 * TiB, "2 TiB"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.size.String())
+		})
+	}
+}
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected ByteSize
+	}{
+		{"1024", 1024},
+		{"512MiB", 512 * MiB},
+		{"512 mb", 512 * MiB},
+		{"4G", 4 * GiB},
+		{"1.5 GiB", 3 * GiB / 2},
+		{"2TB", 2 * TiB},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			size, err := ParseByteSize(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, size)
+		})
+	}
+
+	for _, input := range []string{"", "GB", "4 parsecs", "-1G", "four"} {
+		_, err := ParseByteSize(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestPlanZones(t *testing.T) {
+	provider := AddonProvider{Regions: []string{"par", "rbx"}}
+
+	assert.Equal(t, []string{"mtl"}, provider.PlanZones(AddonPlan{Zones: []string{"mtl"}}))
+	assert.Equal(t, []string{"par", "rbx"}, provider.PlanZones(AddonPlan{}))
+	assert.Empty(t, AddonProvider{}.PlanZones(AddonPlan{}))
+}
+
+func TestDiskUnmarshal(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected Disk
+		wantErr  bool
+	}{
+		{"null", `null`, Disk{}, false},
+		{"mebibytes", `20480`, Disk{Size: 20 * GiB, Known: true}, false},
+		{"object in bytes", `{"unit":"B","value":10737418240,"formatted":"10 GiB"}`, Disk{Size: 10 * GiB, Known: true}, false},
+		{"object in GB", `{"unit":"GB","value":100}`, Disk{Size: 100 * GiB, Known: true}, false},
+		{"unknown unit", `{"unit":"parsecs","value":1}`, Disk{}, true},
+		{"string", `"10 GB"`, Disk{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var disk Disk
+			err := json.Unmarshal([]byte(tt.input), &disk)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, disk)
+		})
+	}
+}
+
+func TestFlavorRoundTrip(t *testing.T) {
+	input := `{"name":"S","mem":2048,"cpus":2,"disk":512,"memory":{"unit":"MB","value":2048,"formatted":"2048 MB"}}`
+
+	var flavor Flavor
+	require.NoError(t, json.Unmarshal([]byte(input), &flavor))
+
+	assert.Equal(t, 2*GiB, flavor.MemorySize())
+	assert.Equal(t, 2048, flavor.Mem)
+	assert.Equal(t, Memory{Unit: "B", Value: 2147483648, Formatted: "2 GiB"}, flavor.Memory)
+	assert.Equal(t, Disk{Size: 512 * MiB, Known: true}, flavor.Disk)
+
+	data, err := json.Marshal(flavor)
+	require.NoError(t, err)
+
+	var decoded Flavor
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, flavor, decoded)
+}
+
+func TestFlavorMemoryFallback(t *testing.T) {
+	var flavor Flavor
+	require.NoError(t, json.Unmarshal([]byte(`{"name":"XS","mem":1024,"disk":null}`), &flavor))
+
+	assert.Equal(t, GiB, flavor.MemorySize())
+	assert.Equal(t, "1 GiB", flavor.Memory.Formatted)
+	assert.False(t, flavor.Disk.Known)
+}
@@ -6,19 +6,48 @@ import "cc-plans-lister/pkg/clevercloud"
 func TestAddonProviders() []clevercloud.AddonProvider {
 	return []clevercloud.AddonProvider{
 		{
-			ID:   "redis",
-			Name: "Redis",
+			ID:           "redis",
+			Name:         "Redis",
+			ShortDesc:    "In-memory key-value store",
+			LongDesc:     "Redis is an open source, in-memory data structure store.",
+			Website:      "https://redis.io",
+			SupportEmail: "support@example.com",
+			Status:       "RELEASE",
+			Regions:      []string{"par", "rbx"},
+			CanUpgrade:   true,
+			LogoURL:      "https://example.com/redis.svg",
 			Plans: []clevercloud.AddonPlan{
-				{ID: "redis_small", Name: "Small Redis", Slug: "small"},
-				{ID: "redis_large", Name: "Large Redis", Slug: "large"},
+				{
+					ID: "redis_small", Name: "Small Redis", Slug: "small", Price: 5, PriceID: "redis_s",
+					Features: []clevercloud.AddonFeature{
+						{Name: "Memory", Type: "BYTES", Value: "256 MB", NameCode: "memory"},
+					},
+					Zones: []string{"par", "rbx"},
+				},
+				{
+					ID: "redis_large", Name: "Large Redis", Slug: "large", Price: 40, PriceID: "redis_l",
+					Features: []clevercloud.AddonFeature{
+						{Name: "Memory", Type: "BYTES", Value: "4 GB", NameCode: "memory"},
+					},
+					Zones: []string{"par"},
+				},
 			},
 		},
 		{
-			ID:   "postgresql",
-			Name: "PostgreSQL",
+			ID:     "postgresql",
+			Name:   "PostgreSQL",
+			Status: "BETA",
 			Plans: []clevercloud.AddonPlan{
 				{ID: "pg_dev", Name: "Dev PostgreSQL", Slug: "dev"},
-				{ID: "pg_prod", Name: "Production PostgreSQL", Slug: "prod"},
+				{
+					ID: "pg_prod", Name: "Production PostgreSQL", Slug: "prod", Price: 20, PriceID: "pg_prod",
+					Features: []clevercloud.AddonFeature{
+						{Name: "Disk size", Type: "BYTES", Value: "10 GB", NameCode: "disk-size"},
+						{Name: "Max connection limit", Type: "NUMBER", Value: "75", NameCode: "connection-limit"},
+						{Name: "Backups", Type: "STRING", Value: "Daily - kept 7 days", NameCode: "backup"},
+					},
+					Zones: []string{"par", "rbx", "mtl"},
+				},
 			},
 		},
 	}
@@ -48,9 +77,9 @@ func TestProductInstances() []clevercloud.ProductInstance {
 					MachineLearning: false,
 					PriceID:         "price_nano_123",
 					Memory: clevercloud.Memory{
-						Unit:      "MB",
-						Value:     256,
-						Formatted: "256 MB",
+						Unit:      "B",
+						Value:     268435456,
+						Formatted: "256 MiB",
 					},
 				},
 				{
@@ -64,10 +93,11 @@ func TestProductInstances() []clevercloud.ProductInstance {
 					MachineLearning: false,
 					PriceID:         "price_small_456",
 					Memory: clevercloud.Memory{
-						Unit:      "MB",
-						Value:     512,
-						Formatted: "512 MB",
+						Unit:      "B",
+						Value:     536870912,
+						Formatted: "512 MiB",
 					},
+					Disk: clevercloud.Disk{Size: 10 * clevercloud.GiB, Known: true},
 				},
 			},
 			DefaultFlavor: clevercloud.Flavor{
@@ -95,9 +125,9 @@ func TestProductInstances() []clevercloud.ProductInstance {
 					MachineLearning: true,
 					PriceID:         "price_py_small_789",
 					Memory: clevercloud.Memory{
-						Unit:      "MB",
-						Value:     512,
-						Formatted: "512 MB",
+						Unit:      "B",
+						Value:     536870912,
+						Formatted: "512 MiB",
 					},
 				},
 			},